@@ -0,0 +1,22 @@
+package parsley
+
+import "github.com/scottkgregory/parsley/internal/cache"
+
+// Cache stores parsed expressions by their source, so that they can be evaluated again without parsing. Implementations
+// must be safe for concurrent use, and may drop expressions whenever they like
+type Cache = cache.Store[string, Node]
+
+// RistrettoConfig sizes a cache backed by github.com/dgraph-io/ristretto/v2. Every expression has a cost of 1, so
+// MaxCost is the number of expressions the cache can hold
+type RistrettoConfig = cache.RistrettoConfig
+
+// NewRistrettoCache returns a cache backed by github.com/dgraph-io/ristretto/v2
+func NewRistrettoCache(cfg RistrettoConfig) (Cache, error) {
+	return cache.NewRistretto(cfg) //nolint:wrapcheck // Error is already wrapped
+}
+
+// NewLRUCache returns a cache holding up to size expressions, evicting the least recently used when it is full. It
+// has no dependencies and costs nothing until it is used, which suits many small parsers
+func NewLRUCache(size int) Cache {
+	return cache.NewLRU(size)
+}
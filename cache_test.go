@@ -0,0 +1,128 @@
+package parsley
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/nodes"
+)
+
+type mapCache struct {
+	items  map[string]Node
+	closed bool
+}
+
+func (c *mapCache) Get(key string) (Node, bool) {
+	n, ok := c.items[key]
+	return n, ok
+}
+
+func (c *mapCache) Set(key string, value Node) { c.items[key] = value }
+
+func (c *mapCache) Delete(key string) { delete(c.items, key) }
+
+func (c *mapCache) Clear() { clear(c.items) }
+
+func (c *mapCache) Close() { c.closed = true }
+
+func TestWithCacheStore(t *testing.T) {
+	store := &mapCache{items: map[string]Node{}}
+
+	parser, err := New(WithCacheStore(store))
+	assert.Nil(t, err)
+
+	actual, err := parser.ParseAsFloat(`1 + 2`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), actual)
+	assert.Equal(t, "1+2", store.items["1 + 2"].String())
+
+	parser.Close()
+	assert.Equal(t, true, store.closed)
+
+	_, err = New(WithCacheStore(nil))
+	assert.ErrorIs(t, ErrInvalidOption, err)
+}
+
+func TestWithLRUCache(t *testing.T) {
+	parser, err := New(WithLRUCache(1))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	for _, input := range []string{`1 + 2`, `2 + 3`, `1 + 2`} {
+		_, err := parser.ParseAsFloat(input, nil)
+		assert.Nil(t, err)
+	}
+
+	_, found := parser.cache.Get(`1 + 2`)
+	assert.Equal(t, true, found)
+
+	_, found = parser.cache.Get(`2 + 3`)
+	assert.Equal(t, false, found)
+
+	_, err = New(WithLRUCache(0))
+	assert.ErrorEqual(t, errors.New("invalid option: cache size must be positive, got 0"), err)
+}
+
+func TestNewRistrettoCache(t *testing.T) {
+	store, err := NewRistrettoCache(RistrettoConfig{NumCounters: 1000, MaxCost: 100, BufferItems: 64})
+	assert.Nil(t, err)
+
+	parser, err := New(WithCacheStore(store))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	actual, err := parser.ParseAsFloat(`1 + 2`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), actual)
+
+	_, err = NewRistrettoCache(RistrettoConfig{})
+	assert.ErrorIs(t, ErrCacheSetup, err)
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	store := &mapCache{items: map[string]Node{}}
+
+	parser, err := New(WithCacheStore(store))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.RegisterFunction("answer", func(_ ...any) (any, error) { return 41, nil })
+
+	actual, err := parser.ParseAsFloat(`answer()`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(41), actual)
+
+	// Replacing the function means the cached expression is parsed again
+	parser.RegisterFunction("answer", func(_ ...any) (any, error) { return 42, nil })
+
+	actual, err = parser.ParseAsFloat(`answer()`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(42), actual)
+
+	// As does registering a node which changes how it's tokenized
+	_, err = parser.ParseAsFloat(`2 £ 3`, nil)
+	assert.Equal(t, true, err != nil)
+
+	parser.RegisterBinaryNode("£", func(left, right Node) Node { return nodes.NewBinaryNode(left, right, "*") })
+
+	actual, err = parser.ParseAsFloat(`2 £ 3`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(6), actual)
+
+	stats := parser.Stats()
+	assert.Equal(t, uint64(0), stats.CacheHits)
+	assert.Equal(t, uint64(4), stats.CacheMisses)
+
+	_, err = parser.ParseAsFloat(`2 £ 3`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), parser.Stats().CacheHits)
+
+	parser.Evict(`answer()`)
+	_, found := store.items[`answer()`]
+	assert.Equal(t, false, found)
+	assert.Equal(t, 1, len(store.items))
+
+	parser.ClearCache()
+	assert.Equal(t, 0, len(store.items))
+}
@@ -0,0 +1,25 @@
+package parsley
+
+import "github.com/scottkgregory/parsley/internal/helpers"
+
+// CoercionPolicy decides which implicit conversions are made when a value of one type is used as another, such as a
+// string in arithmetic or a number in a logical operator
+type CoercionPolicy = helpers.CoercionPolicy
+
+const (
+	// CoercionLenient converts freely between types, numbers and strings such as "yes" can be used as bools and
+	// numeric strings as numbers. nil is treated as an empty string alongside a string. This is the default
+	CoercionLenient = helpers.CoercionLenient
+	// CoercionStrict makes no implicit conversions, values must already have the type they are used as
+	CoercionStrict = helpers.CoercionStrict
+	// CoercionJS follows JavaScript, every value is truthy or falsy, strings are converted to numbers for arithmetic
+	// and comparison, adding a string concatenates and nil is "null" as a string or 0 as a number
+	CoercionJS = helpers.CoercionJS
+)
+
+// SetCoercionPolicy sets the conversions made when evaluating expressions, when converting arguments for functions
+// with a signature and when converting results in ParseAsBool, ParseAsFloat and ParseAsInt. ParseAsString always
+// formats the result. Call this before parsing any expressions
+func (m *Parser) SetCoercionPolicy(policy CoercionPolicy) {
+	m.semantics.Coercion = policy
+}
@@ -0,0 +1,93 @@
+package parsley
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestCoercionPolicy(t *testing.T) {
+	data := map[string]any{"name": "main", "count": 3, "flag": "yes", "missing": nil}
+
+	testCases := []struct {
+		policy CoercionPolicy
+		input  string
+		result bool
+		err    error
+	}{
+		{CoercionLenient, `flag && count`, true, nil},
+		{CoercionLenient, `missing == ""`, true, nil},
+		{CoercionLenient, `"3"`, true, nil},
+		{CoercionStrict, `name == "main" && count > 2`, true, nil},
+		{CoercionStrict, `flag && count`, false, errors.New("error evaluating expression: node evaluation failed: error running comparison: " +
+			"error parsing value as bool, invalid type: string")},
+		{CoercionStrict, `missing == ""`, false, errors.New("error evaluating expression: node evaluation failed: error running comparison: " +
+			"only one side of comparison was a string: <nil> string")},
+		{CoercionStrict, `count`, false, errors.New("error parsing value as bool, invalid type: int")},
+		{CoercionJS, `count == "3"`, true, nil},
+		{CoercionJS, `name + count == "main3"`, true, nil},
+		{CoercionJS, `missing || count - 3`, false, nil},
+		{CoercionJS, `"false"`, true, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.policy.String()+"_"+tc.input, func(t *testing.T) {
+			parser, err := NewParser(false)
+			assert.Nil(t, err)
+			defer parser.Close()
+
+			parser.SetCoercionPolicy(tc.policy)
+
+			actual, err := parser.ParseAsBool(tc.input, data)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
+
+func TestCoercionPolicyFunctions(t *testing.T) {
+	data := map[string]any{
+		"flags":   []any{"yes", "no"},
+		"values":  []any{0, "", "a"},
+		"amounts": []any{"1.5", 2},
+	}
+
+	testCases := []struct {
+		policy CoercionPolicy
+		input  string
+		result any
+		err    error
+	}{
+		{CoercionLenient, `all(flags, f => f)`, false, nil},
+		{CoercionLenient, `sum(amounts)`, 3.5, nil},
+		{CoercionLenient, `sort_by(amounts, a => a)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function sort_by: " +
+			"error running comparison: only one side of comparison was a string: int string")},
+		{CoercionStrict, `any(flags, f => f)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function any: " +
+			"error parsing value as bool, invalid type: string")},
+		{CoercionStrict, `sum(amounts)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function sum: " +
+			"error parsing value as float, invalid type: string")},
+		{CoercionStrict, `count(values, v => v)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function count: " +
+			"error parsing value as bool, invalid type: int")},
+		{CoercionJS, `filter(values, v => v)`, []any{"a"}, nil},
+		{CoercionJS, `count(values, v => v)`, float64(1), nil},
+		{CoercionJS, `map(sort_by(amounts, a => a), a => a)`, []any{"1.5", 2}, nil},
+		{CoercionJS, `twice("2")`, float64(4), nil},
+		{CoercionStrict, `twice("2")`, nil, errors.New("error evaluating expression: node evaluation failed: " +
+			"invalid argument 0 to function twice: error parsing value as float, invalid type: string")},
+		{CoercionStrict, `ceil("2.5")`, nil, errors.New("error evaluating expression: node evaluation failed: " +
+			"invalid argument 0 to function ceil: error parsing value as float, invalid type: string")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.policy.String()+"_"+tc.input, func(t *testing.T) {
+			parser, err := New(WithCoercion(tc.policy), WithLibraries(CollectionLibrary))
+			assert.Nil(t, err)
+			defer parser.Close()
+
+			RegisterFunc1(parser, "twice", func(x float64) (float64, error) { return x * 2, nil })
+
+			actual, err := parser.ParseAsAny(tc.input, data)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package parsley
+
+import "github.com/scottkgregory/parsley/internal/decimal"
+
+// Decimal is an exact decimal number, produced by expressions when decimal arithmetic is enabled
+type Decimal = decimal.Decimal
+
+// DecimalContext controls results which can't be represented exactly, such as 1/3, by setting the number of decimal
+// places to keep and how to round the rest
+type DecimalContext = decimal.Context
+
+// RoundingMode decides what happens to digits beyond the scale of a decimal result
+type RoundingMode = decimal.RoundingMode
+
+const (
+	// RoundHalfEven rounds to the nearest value, ties go to the even neighbour. Also known as banker's rounding
+	RoundHalfEven = decimal.HalfEven
+	// RoundHalfUp rounds to the nearest value, ties go away from zero
+	RoundHalfUp = decimal.HalfUp
+	// RoundHalfDown rounds to the nearest value, ties go towards zero
+	RoundHalfDown = decimal.HalfDown
+	// RoundDown rounds towards zero, truncating the extra digits
+	RoundDown = decimal.Down
+	// RoundUp rounds away from zero
+	RoundUp = decimal.Up
+	// RoundCeiling rounds towards positive infinity
+	RoundCeiling = decimal.Ceiling
+	// RoundFloor rounds towards negative infinity
+	RoundFloor = decimal.Floor
+)
+
+// DefaultDecimalContext keeps 16 decimal places using banker's rounding
+var DefaultDecimalContext = decimal.DefaultContext
+
+// ParseDecimal parses a decimal from a string such as "-12.50"
+func ParseDecimal(s string) (Decimal, error) {
+	return decimal.Parse(s) //nolint:wrapcheck // Error is already wrapped
+}
+
+// EnableDecimal switches number literals and arithmetic on numbers from float64 to exact decimals, so that
+// 0.1 + 0.2 == 0.3. Numbers from the data are converted using their shortest representation, or parsed exactly when
+// they are json.Number. Strings follow the coercion policy as before, so mixing a string with a number is an error
+// unless CoercionJS is used, in which case the string is parsed exactly. ParseAsAny returns a Decimal, use
+// ParseAsString or ParseAsFloat to convert it.
+//
+// Only operators and the sum and avg collection functions are exact. Other functions, including the math library,
+// receive and return float64 numbers, so their results are rounded to the nearest float. Call this before parsing any
+// expressions, as cached expressions keep the literals they were parsed with
+func (m *Parser) EnableDecimal(ctx DecimalContext) {
+	m.semantics.Decimal = &ctx
+}
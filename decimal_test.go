@@ -0,0 +1,177 @@
+package parsley
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestDecimal(t *testing.T) {
+	parser, err := NewParser(true)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.EnableDecimal(DecimalContext{Scale: 2, Rounding: RoundHalfUp})
+
+	data := map[string]any{}
+	decoder := json.NewDecoder(strings.NewReader(`{"total": 100.10, "items": [{"price": 33.37}], "big": 12345678901234567890.01}`))
+	decoder.UseNumber()
+	assert.Nil(t, decoder.Decode(&data))
+	data["discount"] = 0.1
+	data["huge"] = json.Number("1e999999999")
+	data["first_price"] = data["items"].([]any)[0].(map[string]any)["price"]
+
+	testCases := []struct {
+		input  string
+		result string
+		err    error
+	}{
+		{`0.1 + 0.2 == 0.3`, "true", nil},
+		{`0.1 + 0.2`, "0.3", nil},
+		{`total - discount`, "100.00", nil},
+		{`total / 3`, "33.37", nil},
+		{`total / 3 == first_price`, "true", nil},
+		{`big + 0.01`, "12345678901234567890.02", nil},
+		{`-total`, "-100.10", nil},
+		{`1.10 * 3`, "3.30", nil},
+		{`2 ^ -2`, "0.25", nil},
+		{`1 / 0`, "", errors.New("error evaluating expression: node evaluation failed: error running comparison: division by zero")},
+		{`2 ^ 1000000`, "", errors.New("error evaluating expression: node evaluation failed: error running comparison: decimal out of range, 2 ^ 1000000 has more than 10000 digits")},
+		{`huge + 1`, "", errors.New("error evaluating expression: node evaluation failed: error running comparison: error in left side: " +
+			"decimal out of range, '1e999999999' has more than 10000 digits")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsString(tc.input, data)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+
+	f, err := parser.ParseAsFloat(`total / 3`, data)
+	assert.Nil(t, err)
+	assert.Equal(t, 33.37, f)
+
+	b, err := parser.ParseAsBool(`total - 100.10`, data)
+	assert.Nil(t, err)
+	assert.Equal(t, false, b)
+
+	_, err = parser.ParseAsAny(`total % 0`, data)
+	assert.ErrorIs(t, ErrDivisionByZero, err)
+
+	a, err := parser.ParseAsAny(`total * 2`, data)
+	assert.Nil(t, err)
+	expected, err := ParseDecimal("200.20")
+	assert.Nil(t, err)
+	assert.Equal(t, expected, a)
+}
+
+func TestDecimalAggregates(t *testing.T) {
+	parser, err := New(WithDecimal(DecimalContext{Scale: 2, Rounding: RoundHalfUp}), WithLibraries(CollectionLibrary))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	data := map[string]any{
+		"prices":  []any{0.1, 0.2},
+		"amounts": []any{10.005, 1, "2"},
+		"empty":   []any{},
+	}
+
+	testCases := []struct {
+		input  string
+		result string
+		err    error
+	}{
+		{`sum(prices)`, "0.3", nil},
+		{`sum(prices) == 0.3`, "true", nil},
+		{`sum(prices, p => p * 3)`, "0.9", nil},
+		{`avg(prices)`, "0.15", nil},
+		{`sum(amounts)`, "13.005", nil},
+		{`avg(amounts)`, "4.34", nil},
+		{`sum(empty)`, "0", nil},
+		{`avg(empty)`, "", errors.New("error evaluating expression: node evaluation failed: error calling function avg: empty list")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsString(tc.input, data)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
+
+func TestFloatByDefault(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	actual, err := parser.ParseAsBool(`0.1 + 0.2 == 0.3`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, false, actual)
+}
+
+func TestIntegers(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.EnableIntegers()
+
+	data := map[string]any{"id": int64(1234567890123456789), "other": uint64(1234567890123456788), "count": 7}
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`id == other + 1`, true, nil},
+		{`id > other`, true, nil},
+		{`id == 1234567890123456789`, true, nil},
+		{`id == 1234567890123456788`, false, nil},
+		{`id + 1`, int64(1234567890123456790), nil},
+		{`count % 3`, int64(1), nil},
+		{`count * 2 - 1`, int64(13), nil},
+		{`count / 2`, 3.5, nil},
+		{`count / 2.0`, 3.5, nil},
+		{`-count`, int64(-7), nil},
+		{`2 ^ 10`, int64(1024), nil},
+		{`id * 10`, nil, errors.New("error evaluating expression: node evaluation failed: error running comparison: integer overflow: 1234567890123456789 * 10")},
+		{`99999999999999999999`, nil, errors.New("integer overflow: 99999999999999999999")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, data)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+
+	i, err := parser.ParseAsInt(`id - 1`, data)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1234567890123456788), i)
+
+	_, err = parser.ParseAsInt(`count / 2`, data)
+	assert.ErrorEqual(t, errors.New("error parsing value as int, 3.5 is not a whole number"), err)
+
+	for _, input := range []string{`count / 0`, `count % 0`} {
+		_, err = parser.ParseAsAny(input, data)
+		assert.ErrorIs(t, ErrDivisionByZero, err)
+	}
+}
+
+func TestModulo(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	actual, err := parser.ParseAsAny(`10 % 4 + 2 * 7 % 5`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(6), actual)
+
+	i, err := parser.ParseAsInt(`7.5 % 2.5`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+}
@@ -0,0 +1,275 @@
+package parsley
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/nodes"
+)
+
+// ErrInvalidGrammar is returned when registered grammar can't be used
+const ErrInvalidGrammar = helpers.ConstError("invalid grammar")
+
+// PostfixNodeFunc is a constructor for a postfix node, such as the ! in 10!
+type PostfixNodeFunc func(left Node) Node
+
+// NaryNodeFunc is a constructor for a node with several operands, such as x between 1 and 10
+type NaryNodeFunc func(operands ...Node) Node
+
+// ParseHook parses a construct which starts with the token it was registered for. The hook is called with the token
+// as the current token, and must consume everything up to the end of the construct
+type ParseHook func(g Grammar) (Node, error)
+
+// Grammar gives parse hooks access to the parser
+type Grammar interface {
+	// Token returns the current token. Identifiers, numbers and strings are returned as written, strings without
+	// their quotes
+	Token() string
+	// Kind returns the kind of the current token, one of "operator", "identifier", "number", "string" or "eof"
+	Kind() string
+	// Next moves on to the next token
+	Next() error
+	// Expect checks the current token is the one given then moves past it
+	Expect(token string) error
+	// ParseExpression parses a complete expression, stopping at a token which can't continue it such as , or )
+	ParseExpression() (Node, error)
+	// ParseOperand parses an expression containing only operators which bind at least as tightly as the level
+	ParseOperand(level int) (Node, error)
+}
+
+// naryNode is a registered node with several operands separated by keywords
+type naryNode struct {
+	keywords   []string
+	fun        NaryNodeFunc
+	precedence Precedence
+}
+
+// parseHook is a registered parse hook, along with the other tokens it uses
+type parseHook struct {
+	fun    ParseHook
+	tokens []string
+}
+
+// RegisterPostfixNode adds a node which follows its operand, such as 10! or 3d. Postfix operators bind more tightly
+// than any binary operator, so -2! is -(2!)
+func (p *Parser) RegisterPostfixNode(token string, fun PostfixNodeFunc) {
+	p.Registry.RegisterPostfixNode(token, fun)
+}
+
+// RegisterPostfixNode adds a postfix node to the registry, see Parser.RegisterPostfixNode
+func (r *Registry) RegisterPostfixNode(token string, fun PostfixNodeFunc) {
+	r.update(func(s *snapshot) {
+		s.addToken(token)
+		s.postfix[token] = fun
+	})
+}
+
+// UnregisterPostfixNode removes a postfix node added with RegisterPostfixNode
+func (p *Parser) UnregisterPostfixNode(token string) {
+	p.Registry.UnregisterPostfixNode(token)
+}
+
+// UnregisterPostfixNode removes a postfix node added with RegisterPostfixNode
+func (r *Registry) UnregisterPostfixNode(token string) {
+	r.update(func(s *snapshot) {
+		delete(s.postfix, token)
+		s.forgetToken(token)
+	})
+}
+
+// RegisterNaryNode adds a node with several operands separated by keywords. The first keyword follows the first
+// operand like a binary operator, so RegisterNaryNode([]string{"between", "and"}, ...) parses x between 1 and 10 and
+// calls the constructor with x, 1 and 10. The operands bind more tightly than the precedence, which defaults to that
+// of comparisons
+func (p *Parser) RegisterNaryNode(keywords []string, fun NaryNodeFunc, prec ...Precedence) error {
+	return p.Registry.RegisterNaryNode(keywords, fun, prec...)
+}
+
+// RegisterNaryNode adds a node with several operands to the registry, see Parser.RegisterNaryNode
+func (r *Registry) RegisterNaryNode(keywords []string, fun NaryNodeFunc, prec ...Precedence) error {
+	if len(keywords) < 2 {
+		return fmt.Errorf("%w: a node with several operands needs at least 2 keywords, got %d", ErrInvalidGrammar, len(keywords))
+	}
+
+	n := naryNode{keywords: slices.Clone(keywords), fun: fun, precedence: Precedence{Level: PrecedenceComparison}}
+	if len(prec) > 0 {
+		n.precedence = prec[0]
+	}
+
+	r.update(func(s *snapshot) {
+		for _, keyword := range keywords {
+			s.addToken(keyword)
+		}
+
+		s.nary[keywords[0]] = n
+	})
+
+	return nil
+}
+
+// UnregisterNaryNode removes a node added with RegisterNaryNode, given its first keyword
+func (p *Parser) UnregisterNaryNode(keyword string) {
+	p.Registry.UnregisterNaryNode(keyword)
+}
+
+// UnregisterNaryNode removes a node added with RegisterNaryNode, given its first keyword
+func (r *Registry) UnregisterNaryNode(keyword string) {
+	r.update(func(s *snapshot) {
+		n := s.nary[keyword]
+		delete(s.nary, keyword)
+		for _, k := range n.keywords {
+			s.forgetToken(k)
+		}
+	})
+}
+
+// RegisterParseHook calls the hook to parse an operand starting with the token, so that plugins can add grammar such
+// as list literals or if ... then ... else. Any other tokens used by the construct, such as ] or then, must be given
+// so that they are recognised. Panics in the hook are returned as errors
+func (p *Parser) RegisterParseHook(token string, hook ParseHook, tokens ...string) {
+	p.Registry.RegisterParseHook(token, hook, tokens...)
+}
+
+// RegisterParseHook adds a parse hook to the registry, see Parser.RegisterParseHook
+func (r *Registry) RegisterParseHook(token string, hook ParseHook, tokens ...string) {
+	r.update(func(s *snapshot) {
+		s.addToken(token)
+		for _, tok := range tokens {
+			s.addToken(tok)
+		}
+
+		s.hooks[token] = parseHook{fun: hook, tokens: slices.Clone(tokens)}
+	})
+}
+
+// UnregisterParseHook removes a hook added with RegisterParseHook
+func (p *Parser) UnregisterParseHook(token string) {
+	p.Registry.UnregisterParseHook(token)
+}
+
+// UnregisterParseHook removes a hook added with RegisterParseHook
+func (r *Registry) UnregisterParseHook(token string) {
+	r.update(func(s *snapshot) {
+		h := s.hooks[token]
+		delete(s.hooks, token)
+		s.forgetToken(token)
+		for _, tok := range h.tokens {
+			s.forgetToken(tok)
+		}
+	})
+}
+
+// grammar gives parse hooks access to a parser
+type grammar struct {
+	p *parser
+}
+
+func (g grammar) Token() string {
+	t := g.p.tokenizer
+	switch t.Token {
+	case identifier:
+		return t.Identifier
+	case number:
+		return t.NumberText
+	case str:
+		return t.String
+	}
+
+	return t.Token
+}
+
+func (g grammar) Kind() string {
+	switch g.p.tokenizer.Token {
+	case identifier:
+		return "identifier"
+	case number:
+		return "number"
+	case str:
+		return "string"
+	case eof:
+		return "eof"
+	}
+
+	return "operator"
+}
+
+func (g grammar) Next() error {
+	return g.p.tokenizer.NextToken()
+}
+
+func (g grammar) Expect(token string) error {
+	if g.p.tokenizer.Token != token {
+		return fmt.Errorf("expected %s, got %s", token, g.p.tokenizer.Token)
+	}
+
+	return g.p.tokenizer.NextToken()
+}
+
+func (g grammar) ParseExpression() (Node, error) {
+	return g.p.parseBinary(precedenceLowest)
+}
+
+func (g grammar) ParseOperand(level int) (Node, error) {
+	return g.p.parseBinary(level)
+}
+
+// parseHook calls a registered parse hook, wrapping the node so that panics in user code are returned as errors
+func (p *parser) parseHook(token string, hook parseHook) (node nodes.Node, err error) {
+	defer nodes.Recover(token, &err)
+
+	node, err = hook.fun(grammar{p})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes.NewRecoverNode(token, node), nil
+}
+
+// parsePostfix applies any postfix operators following the operand
+func (p *parser) parsePostfix(operand nodes.Node) (nodes.Node, error) {
+	for {
+		n, ok := p.reg.postfix[p.tokenizer.Token]
+		if !ok {
+			return operand, nil
+		}
+
+		token := p.tokenizer.Token
+		err := p.tokenizer.NextToken()
+		if err != nil {
+			return nil, err
+		}
+
+		left := operand
+		operand, err = construct(token, func() nodes.Node { return n(left) })
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// naryNode parses the remaining operands of a node with several operands, the first keyword has been consumed
+func (p *parser) naryNode(n naryNode, first nodes.Node) (nodes.Node, error) {
+	operands := []nodes.Node{first}
+	for i := 1; i <= len(n.keywords); i++ {
+		if i > 1 {
+			if p.tokenizer.Token != n.keywords[i-1] {
+				return nil, fmt.Errorf("expected %s after %s", n.keywords[i-1], n.keywords[i-2])
+			}
+
+			err := p.tokenizer.NextToken()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		operand, err := p.parseBinary(n.precedence.Level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		operands = append(operands, operand)
+	}
+
+	return construct(n.keywords[0], func() nodes.Node { return n.fun(operands...) })
+}
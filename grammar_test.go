@@ -0,0 +1,167 @@
+package parsley
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+// funcNode evaluates its operands and passes them to a function
+type funcNode struct {
+	name     string
+	operands []Node
+	fun      func(args ...float64) any
+}
+
+func (n *funcNode) Eval(vars map[string]any) (any, error) {
+	args := make([]float64, len(n.operands))
+	for i, operand := range n.operands {
+		v, err := operand.Eval(vars)
+		if err != nil {
+			return nil, err
+		}
+
+		args[i], err = helpers.ToFloat64(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return n.fun(args...), nil
+}
+
+func (n *funcNode) String() string {
+	operands := make([]string, len(n.operands))
+	for i, operand := range n.operands {
+		operands[i] = operand.String()
+	}
+
+	return n.name + "(" + strings.Join(operands, ", ") + ")"
+}
+
+// listNode is a list literal such as [1, 2]
+type listNode []Node
+
+func (n listNode) Eval(vars map[string]any) (any, error) {
+	ret := make([]any, len(n))
+	for i, item := range n {
+		var err error
+		ret[i], err = item.Eval(vars)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+func (n listNode) String() string {
+	return "[...]"
+}
+
+func parseList(g Grammar) (Node, error) {
+	err := g.Expect("[")
+	if err != nil {
+		return nil, err
+	}
+
+	var list listNode
+	for g.Token() != "]" {
+		item, err := g.ParseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, item)
+		if g.Token() != "," {
+			break
+		}
+
+		err = g.Next()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return list, g.Expect("]")
+}
+
+func TestGrammar(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.RegisterPostfixNode("!", func(left Node) Node {
+		return &funcNode{"factorial", []Node{left}, func(args ...float64) any {
+			ret := 1.0
+			for i := 2.0; i <= args[0]; i++ {
+				ret *= i
+			}
+
+			return ret
+		}}
+	})
+	parser.RegisterPostfixNode("d", func(left Node) Node {
+		return &funcNode{"days", []Node{left}, func(args ...float64) any {
+			return time.Duration(args[0]) * 24 * time.Hour
+		}}
+	})
+
+	err = parser.RegisterNaryNode([]string{"between", "and"}, func(operands ...Node) Node {
+		return &funcNode{"between", operands, func(args ...float64) any {
+			return args[0] >= args[1] && args[0] <= args[2]
+		}}
+	})
+	assert.Nil(t, err)
+
+	parser.RegisterParseHook("[", parseList, "]")
+	parser.RegisterParseHook("boom", func(_ Grammar) (Node, error) { panic("hook") })
+
+	testCases := []struct {
+		input  string
+		vars   map[string]any
+		result any
+		err    error
+	}{
+		{`5!`, nil, float64(120), nil},
+		{`-3!`, nil, float64(-6), nil},
+		{`2 + 3! * 2`, nil, float64(14), nil},
+		{`3!!`, nil, float64(720), nil},
+		{`(1 + 2)!`, nil, float64(6), nil},
+		{`3d`, nil, 72 * time.Hour, nil},
+		{`x + d`, map[string]any{"x": 1}, nil, errors.New("unexpected token: d")},
+		{`x between 1 and 10`, map[string]any{"x": 5}, true, nil},
+		{`x between 1 and 10`, map[string]any{"x": 11}, false, nil},
+		{`x + 1 between 2 * 3 and 10 && x > 2`, map[string]any{"x": 5}, true, nil},
+		{`x between 1`, map[string]any{"x": 5}, nil, errors.New("expected and after between")},
+		{`[1, 2 + 3, "a"]`, nil, []any{float64(1), float64(5), "a"}, nil},
+		{`[]`, nil, []any{}, nil},
+		{`[1, 2`, nil, nil, errors.New("expected ], got EOF")},
+		{`boom`, nil, nil, errors.New("function panicked: boom: hook")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, tc.vars)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+
+	parser.UnregisterParseHook("[")
+	_, err = parser.ParseAsAny(`[1]`, nil)
+	assert.ErrorEqual(t, errors.New("unexpected character: ["), err)
+}
+
+func TestRegisterNaryNodeErrors(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	err = parser.RegisterNaryNode([]string{"between"}, func(_ ...Node) Node { return &testNode{} })
+	assert.ErrorEqual(t, errors.New("invalid grammar: a node with several operands needs at least 2 keywords, got 1"), err)
+	assert.ErrorIs(t, ErrInvalidGrammar, err)
+}
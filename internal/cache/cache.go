@@ -2,9 +2,6 @@
 package cache
 
 import (
-	"fmt"
-
-	"github.com/dgraph-io/ristretto/v2"
 	"github.com/scottkgregory/parsley/internal/helpers"
 	"github.com/scottkgregory/parsley/internal/nodes"
 )
@@ -12,45 +9,28 @@ import (
 // ErrCacheSetup is returned when setting up the cache fails
 const ErrCacheSetup = helpers.ConstError("error setting up cache")
 
-// Store provides caching functions
+// Store provides caching functions, implementations must be safe for concurrent use
 type Store[K string, V any] interface {
 	Get(key K) (V, bool)
 	Set(key K, value V)
+	Delete(key K)
+	Clear()
 	Close()
 }
 
-// NewCache returns a new cache backed by github.com/dgraph-io/ristretto/v2
-func NewCache() (Store[string, nodes.Node], error) {
-	inner, err := ristretto.NewCache(&ristretto.Config[string, nodes.Node]{
-		NumCounters: 1e7,
-		MaxCost:     1 << 30,
-		BufferItems: 64,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrCacheSetup, err)
-	}
-
-	return &cache[string, nodes.Node]{inner}, nil
+// Metrics are the statistics a store keeps about itself
+type Metrics struct {
+	Hits         uint64
+	Misses       uint64
+	KeysAdded    uint64
+	KeysEvicted  uint64
+	SetsDropped  uint64
+	SetsRejected uint64
 }
 
-type cache[K string, V any] struct {
-	inner *ristretto.Cache[K, V]
-}
-
-// Get gets a value from the cache, if available
-func (c *cache[K, V]) Get(key K) (V, bool) {
-	return c.inner.Get(key)
-}
-
-// Set sets a value in the cache
-func (c *cache[K, V]) Set(key K, value V) {
-	c.inner.Set(key, value, 0)
-	c.inner.Wait()
-}
-
-// Close releases any underlying resources
-func (c *cache[K, V]) Close() {
-	c.inner.Close()
+// MetricsStore is implemented by stores which keep statistics about themselves
+type MetricsStore interface {
+	Metrics() (Metrics, bool)
 }
 
 // NewNoOpCache returns an implementation of the cache interface which doesn't do any caching
@@ -68,5 +48,11 @@ func (c *noOpCache[K, V]) Get(_ K) (V, bool) {
 // Set sets a value in the cache
 func (c *noOpCache[K, V]) Set(_ K, _ V) {}
 
+// Delete removes a value from the cache
+func (c *noOpCache[K, V]) Delete(_ K) {}
+
+// Clear removes every value from the cache
+func (c *noOpCache[K, V]) Clear() {}
+
 // Close releases any underlying resources
 func (c *noOpCache[K, V]) Close() {}
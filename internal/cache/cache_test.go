@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/scottkgregory/parsley/internal/assert"
@@ -8,13 +9,16 @@ import (
 )
 
 func TestCache(t *testing.T) {
-	c, err := NewCache()
+	c, err := NewCache(DefaultSize)
 	assert.Nil(t, err)
 
 	node := nodes.NewNumberNode(12)
 
 	c.Set("a", node)
 
+	// Sets are buffered
+	c.(*cache[string, nodes.Node]).inner.Wait()
+
 	actual, present := c.Get("a")
 	assert.Equal(t, node, actual)
 	assert.Equal(t, true, present)
@@ -23,9 +27,30 @@ func TestCache(t *testing.T) {
 	assert.Equal(t, nil, actual)
 	assert.Equal(t, false, present)
 
+	c.Delete("a")
+	c.(*cache[string, nodes.Node]).inner.Wait()
+
+	_, present = c.Get("a")
+	assert.Equal(t, false, present)
+
+	c.Set("b", node)
+	c.(*cache[string, nodes.Node]).inner.Wait()
+	c.Clear()
+
+	_, present = c.Get("b")
+	assert.Equal(t, false, present)
+
 	c.Close()
 }
 
+func TestNewRistretto(t *testing.T) {
+	_, err := NewRistretto(RistrettoConfig{MaxCost: 10, BufferItems: 64})
+	assert.ErrorEqual(t, errors.New("error setting up cache: NumCounters can't be zero"), err)
+	assert.ErrorIs(t, ErrCacheSetup, err)
+
+	assert.Equal(t, RistrettoConfig{NumCounters: 1000, MaxCost: 100, BufferItems: 64}, SizedRistrettoConfig(100))
+}
+
 func TestNoOpCacheGet(t *testing.T) {
 	node, present := NewNoOpCache().Get("key")
 	assert.Equal(t, false, present)
@@ -36,6 +61,12 @@ func TestNoOpCacheSet(_ *testing.T) {
 	NewNoOpCache().Set("key", nodes.NewNumberNode(12))
 }
 
+func TestNoOpCacheDelete(_ *testing.T) {
+	c := NewNoOpCache()
+	c.Delete("key")
+	c.Clear()
+}
+
 func TestNoOpCacheClose(_ *testing.T) {
 	NewNoOpCache().Close()
 }
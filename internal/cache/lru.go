@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/scottkgregory/parsley/internal/nodes"
+)
+
+// NewLRU returns a new cache holding up to size expressions, evicting the least recently used when it is full
+func NewLRU(size int) Store[string, nodes.Node] {
+	return &lru[string, nodes.Node]{
+		size:  max(size, 1),
+		order: list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+type lru[K string, V any] struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Most recently used first
+	items   map[K]*list.Element
+	metrics Metrics
+}
+
+type entry[K string, V any] struct {
+	key   K
+	value V
+}
+
+// Get gets a value from the cache, if available
+func (c *lru[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		c.metrics.Misses++
+		return *new(V), false
+	}
+
+	c.metrics.Hits++
+	c.order.MoveToFront(e)
+
+	return e.Value.(*entry[K, V]).value, true
+}
+
+// Set sets a value in the cache
+func (c *lru[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value.(*entry[K, V]).value = value
+		c.order.MoveToFront(e)
+
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key, value})
+	c.metrics.KeysAdded++
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[K, V]).key)
+		c.metrics.KeysEvicted++
+	}
+}
+
+// Delete removes a value from the cache
+func (c *lru[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.order.Remove(e)
+		delete(c.items, key)
+	}
+}
+
+// Clear removes every value from the cache
+func (c *lru[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.items)
+}
+
+// Metrics returns the statistics kept by the cache
+func (c *lru[K, V]) Metrics() (Metrics, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.metrics, true
+}
+
+// Close releases any underlying resources
+func (c *lru[K, V]) Close() {
+	c.Clear()
+}
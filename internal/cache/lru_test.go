@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/nodes"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU(2)
+	a, b, d := nodes.NewNumberNode(1), nodes.NewNumberNode(2), nodes.NewNumberNode(3)
+
+	c.Set("a", a)
+	c.Set("b", b)
+
+	// Using a makes b the least recently used
+	actual, present := c.Get("a")
+	assert.Equal(t, a, actual)
+	assert.Equal(t, true, present)
+
+	c.Set("d", d)
+
+	_, present = c.Get("b")
+	assert.Equal(t, false, present)
+
+	actual, present = c.Get("a")
+	assert.Equal(t, a, actual)
+	assert.Equal(t, true, present)
+
+	actual, present = c.Get("d")
+	assert.Equal(t, d, actual)
+	assert.Equal(t, true, present)
+
+	// Replacing a value doesn't evict anything
+	c.Set("a", b)
+	actual, _ = c.Get("a")
+	assert.Equal(t, b, actual)
+
+	_, present = c.Get("d")
+	assert.Equal(t, true, present)
+
+	c.Delete("a")
+	_, present = c.Get("a")
+	assert.Equal(t, false, present)
+
+	c.Set("a", a)
+	c.Clear()
+	_, present = c.Get("d")
+	assert.Equal(t, false, present)
+
+	c.Set("a", a)
+	c.Close()
+
+	_, present = c.Get("a")
+	assert.Equal(t, false, present)
+}
+
+func TestLRUConcurrent(t *testing.T) {
+	c := NewLRU(10)
+	wg := sync.WaitGroup{}
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				key := fmt.Sprint((i + j) % 20)
+				c.Set(key, nodes.NewNumberNode(j))
+				c.Get(key)
+			}
+		}()
+	}
+	wg.Wait()
+
+	n := 0
+	for i := range 20 {
+		if _, ok := c.Get(fmt.Sprint(i)); ok {
+			n++
+		}
+	}
+	assert.Equal(t, 10, n)
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/scottkgregory/parsley/internal/nodes"
+)
+
+// DefaultSize is the number of expressions kept by a cache unless another size is given. Ristretto allocates its
+// counters up front, so the default is kept small enough for a parser to be cheap to create
+const DefaultSize = 10_000
+
+// RistrettoConfig sizes a cache backed by github.com/dgraph-io/ristretto/v2. Every expression has a cost of 1
+type RistrettoConfig struct {
+	// NumCounters is the number of keys to track how often they are used, ristretto recommends ten times MaxCost
+	NumCounters int64
+	// MaxCost is the number of expressions the cache can hold
+	MaxCost int64
+	// BufferItems is the size of the buffers used to batch reads, ristretto recommends 64
+	BufferItems int64
+	// Metrics keeps statistics such as hits and evictions, at a small cost to performance
+	Metrics bool
+}
+
+// SizedRistrettoConfig returns the recommended config for a cache holding up to size expressions
+func SizedRistrettoConfig(size int64) RistrettoConfig {
+	return RistrettoConfig{NumCounters: 10 * size, MaxCost: size, BufferItems: 64}
+}
+
+// NewCache returns a new cache backed by github.com/dgraph-io/ristretto/v2, holding up to size expressions
+func NewCache(size int64) (Store[string, nodes.Node], error) {
+	return NewRistretto(SizedRistrettoConfig(size))
+}
+
+// NewRistretto returns a new cache backed by github.com/dgraph-io/ristretto/v2 using the given config
+func NewRistretto(cfg RistrettoConfig) (Store[string, nodes.Node], error) {
+	inner, err := ristretto.NewCache(&ristretto.Config[string, nodes.Node]{
+		NumCounters: cfg.NumCounters,
+		MaxCost:     cfg.MaxCost,
+		BufferItems: cfg.BufferItems,
+		Metrics:     cfg.Metrics,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheSetup, err)
+	}
+
+	return &cache[string, nodes.Node]{inner}, nil
+}
+
+type cache[K string, V any] struct {
+	inner *ristretto.Cache[K, V]
+}
+
+// Get gets a value from the cache, if available
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	return c.inner.Get(key)
+}
+
+// Set sets a value in the cache. Sets are buffered, so the value may not be available straight away and may be
+// dropped under contention, which only means the expression is parsed again
+func (c *cache[K, V]) Set(key K, value V) {
+	c.inner.Set(key, value, 1)
+}
+
+// Delete removes a value from the cache
+func (c *cache[K, V]) Delete(key K) {
+	c.inner.Del(key)
+}
+
+// Clear removes every value from the cache
+func (c *cache[K, V]) Clear() {
+	c.inner.Clear()
+}
+
+// Metrics returns the statistics kept by ristretto, if they are enabled
+func (c *cache[K, V]) Metrics() (Metrics, bool) {
+	m := c.inner.Metrics
+	if m == nil {
+		return Metrics{}, false
+	}
+
+	return Metrics{
+		Hits:         m.Hits(),
+		Misses:       m.Misses(),
+		KeysAdded:    m.KeysAdded(),
+		KeysEvicted:  m.KeysEvicted(),
+		SetsDropped:  m.SetsDropped(),
+		SetsRejected: m.SetsRejected(),
+	}, true
+}
+
+// Close releases any underlying resources
+func (c *cache[K, V]) Close() {
+	c.inner.Close()
+}
@@ -0,0 +1,381 @@
+// Package decimal provides an exact decimal number type built on math/big
+package decimal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+const (
+	// ErrInvalidDecimal is returned when a value fails to parse as a decimal
+	ErrInvalidDecimal = helpers.ConstError("error parsing value as decimal")
+
+	// ErrDivisionByZero is returned when dividing a decimal by zero
+	ErrDivisionByZero = helpers.ErrDivisionByZero
+
+	// ErrOutOfRange is returned when a decimal would have more digits than MaxDigits
+	ErrOutOfRange = helpers.ConstError("decimal out of range")
+)
+
+// MaxDigits bounds the exponent of parsed decimals and the number of digits in a power, so that a single expression
+// or value such as 1e999999999 can't use unbounded memory
+const MaxDigits = 10_000
+
+// RoundingMode decides what happens to digits beyond the scale of a result
+type RoundingMode int
+
+const (
+	// HalfEven rounds to the nearest value, ties go to the even neighbour. Also known as banker's rounding
+	HalfEven RoundingMode = iota
+	// HalfUp rounds to the nearest value, ties go away from zero
+	HalfUp
+	// HalfDown rounds to the nearest value, ties go towards zero
+	HalfDown
+	// Down rounds towards zero, truncating the extra digits
+	Down
+	// Up rounds away from zero
+	Up
+	// Ceiling rounds towards positive infinity
+	Ceiling
+	// Floor rounds towards negative infinity
+	Floor
+)
+
+// Context controls results which can't be represented exactly, such as 1/3
+type Context struct {
+	// Scale is the number of digits kept after the decimal point
+	Scale int
+	// Rounding decides how the remaining digits are dropped
+	Rounding RoundingMode
+}
+
+// DefaultContext keeps 16 decimal places using banker's rounding
+var DefaultContext = Context{Scale: 16, Rounding: HalfEven}
+
+// Decimal is an exact decimal number. The zero value is 0
+type Decimal struct {
+	// The value is unscaled × 10^-scale, nil is treated as zero
+	unscaled *big.Int
+	scale    int
+}
+
+// Parse parses a decimal from a string such as "-12.50" or "1.5e3"
+func Parse(s string) (Decimal, error) {
+	mantissa, exponent := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		exponent, err = strconv.Atoi(s[i+1:])
+		if err != nil {
+			return Decimal{}, fmt.Errorf("%w, could not parse string '%s'", ErrInvalidDecimal, s)
+		}
+	}
+
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	digits := whole + fraction
+	if strings.TrimLeft(digits, "+-") == "" || strings.ContainsAny(fraction, "+-") {
+		return Decimal{}, fmt.Errorf("%w, could not parse string '%s'", ErrInvalidDecimal, s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w, could not parse string '%s'", ErrInvalidDecimal, s)
+	}
+
+	scale := len(fraction) - exponent
+	if scale > MaxDigits || scale < -MaxDigits {
+		return Decimal{}, fmt.Errorf("%w, '%s' has more than %d digits", ErrOutOfRange, s, MaxDigits)
+	}
+
+	return Decimal{unscaled, scale}.normalizeScale(), nil
+}
+
+// FromInt creates a decimal from an integer
+func FromInt(i int64) Decimal {
+	return Decimal{big.NewInt(i), 0}
+}
+
+// FromUint creates a decimal from an unsigned integer
+func FromUint(i uint64) Decimal {
+	return Decimal{new(big.Int).SetUint64(i), 0}
+}
+
+// FromFloat creates a decimal from the shortest decimal representation of the float, so 0.1 becomes exactly 0.1
+func FromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("%w, invalid float %v", ErrInvalidDecimal, f)
+	}
+
+	return Parse(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// From converts the input value in to a decimal. Integers are converted exactly, floats use their shortest decimal
+// representation and strings, including json.Number, are parsed
+func From(e any) (Decimal, error) {
+	switch x := e.(type) {
+	case Decimal:
+		return x, nil
+	case int:
+		return FromInt(int64(x)), nil
+	case int8:
+		return FromInt(int64(x)), nil
+	case int16:
+		return FromInt(int64(x)), nil
+	case int32:
+		return FromInt(int64(x)), nil
+	case int64:
+		return FromInt(x), nil
+	case uint:
+		return FromUint(uint64(x)), nil
+	case uint8:
+		return FromUint(uint64(x)), nil
+	case uint16:
+		return FromUint(uint64(x)), nil
+	case uint32:
+		return FromUint(uint64(x)), nil
+	case uint64:
+		return FromUint(x), nil
+	case float32:
+		return FromFloat(float64(x))
+	case float64:
+		return FromFloat(x)
+	case string:
+		return Parse(x)
+	case json.Number:
+		return Parse(string(x))
+	}
+
+	return Decimal{}, fmt.Errorf("%w, invalid type: %T", ErrInvalidDecimal, e)
+}
+
+// Add returns d + e
+func (d Decimal) Add(e Decimal) Decimal {
+	x, y, scale := align(d, e)
+	return Decimal{x.Add(x, y), scale}
+}
+
+// Sub returns d - e
+func (d Decimal) Sub(e Decimal) Decimal {
+	x, y, scale := align(d, e)
+	return Decimal{x.Sub(x, y), scale}
+}
+
+// Mul returns d × e
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{new(big.Int).Mul(d.int(), e.int()), d.scale + e.scale}
+}
+
+// Div returns d / e, rounded to the scale of the context when it can't be represented exactly
+func (d Decimal) Div(e Decimal, ctx Context) (Decimal, error) {
+	if e.Sign() == 0 {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	// d / e = (d.unscaled × 10^e.scale) / (e.unscaled × 10^d.scale)
+	num := new(big.Int).Mul(d.int(), pow10(max(e.scale-d.scale, 0)))
+	den := new(big.Int).Mul(e.int(), pow10(max(d.scale-e.scale, 0)))
+
+	return round(num, den, ctx).trim(), nil
+}
+
+// Mod returns the remainder of d / e, which has the same sign as d
+func (d Decimal) Mod(e Decimal) (Decimal, error) {
+	if e.Sign() == 0 {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	x, y, scale := align(d, e)
+	return Decimal{x.Rem(x, y), scale}, nil
+}
+
+// Pow returns d raised to the power of an integer, negative powers are divided using the context
+func (d Decimal) Pow(n int64, ctx Context) (Decimal, error) {
+	if n < 0 {
+		p, err := d.Pow(-n, ctx)
+		if err != nil {
+			return Decimal{}, err
+		}
+
+		return FromInt(1).Div(p, ctx)
+	}
+
+	// Estimate the number of digits in the result before calculating it
+	d = d.trim()
+	if n > 1 && d.Sign() != 0 {
+		f, _ := new(big.Float).SetInt(new(big.Int).Abs(d.int())).Float64()
+		digits := float64(n) * math.Log10(f)
+		if digits > MaxDigits || d.scale*int(min(n, MaxDigits+1)) > MaxDigits {
+			return Decimal{}, fmt.Errorf("%w, %s ^ %d has more than %d digits", ErrOutOfRange, d, n, MaxDigits)
+		}
+	}
+
+	return Decimal{new(big.Int).Exp(d.int(), big.NewInt(n), nil), d.scale * int(n)}, nil
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{new(big.Int).Neg(d.int()), d.scale}
+}
+
+// Round returns d rounded to the given number of decimal places
+func (d Decimal) Round(ctx Context) Decimal {
+	if d.scale <= ctx.Scale {
+		return d
+	}
+
+	return round(d.int(), pow10(d.scale), ctx)
+}
+
+// Cmp compares d and e, returning -1, 0 or +1
+func (d Decimal) Cmp(e Decimal) int {
+	x, y, _ := align(d, e)
+	return x.Cmp(y)
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsInteger checks whether d has no fractional part
+func (d Decimal) IsInteger() bool {
+	if d.scale <= 0 {
+		return true
+	}
+
+	return new(big.Int).Rem(d.int(), pow10(d.scale)).Sign() == 0
+}
+
+// Int64 returns the integer part of d, and whether it fits in an int64
+func (d Decimal) Int64() (int64, bool) {
+	i := new(big.Int).Quo(d.int(), pow10(d.scale))
+	return i.Int64(), i.IsInt64()
+}
+
+// Float64 returns the nearest float64 to d
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.int(), pow10(d.scale)).Float64()
+	return f
+}
+
+// String returns d in plain decimal notation, keeping any trailing zeros after the decimal point
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+
+	if d.scale == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+
+	point := len(digits) - d.scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return d.unscaled
+}
+
+// normalizeScale removes a negative scale, so that every decimal is an integer number of units of 10^-scale
+func (d Decimal) normalizeScale() Decimal {
+	if d.scale >= 0 {
+		return d
+	}
+
+	return Decimal{new(big.Int).Mul(d.int(), pow10(-d.scale)), 0}
+}
+
+// trim removes trailing zeros after the decimal point
+func (d Decimal) trim() Decimal {
+	x := new(big.Int).Set(d.int())
+	scale := d.scale
+	ten := big.NewInt(10)
+	r := new(big.Int)
+	for scale > 0 {
+		q, m := new(big.Int).QuoRem(x, ten, r)
+		if m.Sign() != 0 {
+			break
+		}
+
+		x = q
+		scale--
+	}
+
+	return Decimal{x, scale}
+}
+
+// align returns copies of the unscaled values of both decimals at the same scale
+func align(d, e Decimal) (*big.Int, *big.Int, int) {
+	scale := max(d.scale, e.scale)
+	x := new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+	y := new(big.Int).Mul(e.int(), pow10(scale-e.scale))
+
+	return x, y, scale
+}
+
+// round divides num by den, keeping the number of decimal places given by the context
+func round(num, den *big.Int, ctx Context) Decimal {
+	scale := ctx.Scale
+	if scale >= 0 {
+		num = new(big.Int).Mul(num, pow10(scale))
+	} else {
+		den = new(big.Int).Mul(den, pow10(-scale))
+	}
+
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return Decimal{q, scale}.normalizeScale()
+	}
+
+	// Positive when the result is positive, as QuoRem truncates towards zero
+	sign := num.Sign() * den.Sign()
+
+	// Compare the remainder with half of the divisor
+	half := new(big.Int).Abs(r)
+	half.Mul(half, big.NewInt(2))
+	c := half.Cmp(new(big.Int).Abs(den))
+
+	var away bool
+	switch ctx.Rounding {
+	case HalfEven:
+		away = c > 0 || (c == 0 && q.Bit(0) == 1)
+	case HalfUp:
+		away = c >= 0
+	case HalfDown:
+		away = c > 0
+	case Down:
+		away = false
+	case Up:
+		away = true
+	case Ceiling:
+		away = sign > 0
+	case Floor:
+		away = sign < 0
+	}
+
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+
+	return Decimal{q, scale}.normalizeScale()
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,209 @@
+package decimal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func mustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input  string
+		result string
+		err    error
+	}{
+		{"0", "0", nil},
+		{"12", "12", nil},
+		{"-12.50", "-12.50", nil},
+		{"+0.001", "0.001", nil},
+		{".5", "0.5", nil},
+		{"1.5e3", "1500", nil},
+		{"1.5E-3", "0.0015", nil},
+		{"123456789012345678901234567890.123456789", "123456789012345678901234567890.123456789", nil},
+		{"", "0", errors.New("error parsing value as decimal, could not parse string ''")},
+		{"-", "0", errors.New("error parsing value as decimal, could not parse string '-'")},
+		{"1.2.3", "0", errors.New("error parsing value as decimal, could not parse string '1.2.3'")},
+		{"1.-2", "0", errors.New("error parsing value as decimal, could not parse string '1.-2'")},
+		{"1e", "0", errors.New("error parsing value as decimal, could not parse string '1e'")},
+		{"abc", "0", errors.New("error parsing value as decimal, could not parse string 'abc'")},
+		{"1e10000", "1" + strings.Repeat("0", 10000), nil},
+		{"1e999999999", "0", errors.New("decimal out of range, '1e999999999' has more than 10000 digits")},
+		{"1e-999999999", "0", errors.New("decimal out of range, '1e-999999999' has more than 10000 digits")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := Parse(tc.input)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual.String())
+			if tc.err != nil && !errors.Is(err, ErrOutOfRange) {
+				assert.ErrorIs(t, ErrInvalidDecimal, err)
+			}
+		})
+	}
+}
+
+func TestFrom(t *testing.T) {
+	testCases := []struct {
+		input  any
+		result string
+		err    error
+	}{
+		{mustParse("1.10"), "1.10", nil},
+		{int8(-3), "-3", nil},
+		{uint64(math.MaxUint64), "18446744073709551615", nil},
+		{int64(math.MaxInt64), "9223372036854775807", nil},
+		{0.1, "0.1", nil},
+		{float32(0.5), "0.5", nil},
+		{1e21, "1000000000000000000000", nil},
+		{"2.50", "2.50", nil},
+		{json.Number("9007199254740993.01"), "9007199254740993.01", nil},
+		{math.NaN(), "0", errors.New("error parsing value as decimal, invalid float NaN")},
+		{true, "0", errors.New("error parsing value as decimal, invalid type: bool")},
+		{nil, "0", errors.New("error parsing value as decimal, invalid type: <nil>")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%T_%v", tc.input, tc.input), func(t *testing.T) {
+			actual, err := From(tc.input)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual.String())
+		})
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := mustParse("0.1"), mustParse("0.2")
+	assert.Equal(t, "0.3", a.Add(b).String())
+	assert.Equal(t, 0, a.Add(b).Cmp(mustParse("0.30")))
+	assert.Equal(t, "-0.1", a.Sub(b).String())
+	assert.Equal(t, "0.02", a.Mul(b).String())
+	assert.Equal(t, "-0.1", a.Neg().String())
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.Equal(t, "1.10", mustParse("1.10").Add(Decimal{}).String())
+
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, 1, b.Cmp(a))
+	assert.Equal(t, -1, a.Neg().Sign())
+	assert.Equal(t, 0.3, a.Add(b).Float64())
+
+	assert.Equal(t, true, mustParse("12.000").IsInteger())
+	assert.Equal(t, false, mustParse("12.001").IsInteger())
+
+	i, ok := mustParse("-12.9").Int64()
+	assert.Equal(t, int64(-12), i)
+	assert.Equal(t, true, ok)
+
+	_, ok = mustParse("1e30").Int64()
+	assert.Equal(t, false, ok)
+
+	p, err := mustParse("1.5").Pow(3, DefaultContext)
+	assert.Nil(t, err)
+	assert.Equal(t, "3.375", p.String())
+
+	p, err = mustParse("2").Pow(-2, DefaultContext)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.25", p.String())
+
+	_, err = Decimal{}.Pow(-1, DefaultContext)
+	assert.ErrorIs(t, ErrDivisionByZero, err)
+
+	_, err = mustParse("2").Pow(1_000_000, DefaultContext)
+	assert.ErrorEqual(t, errors.New("decimal out of range, 2 ^ 1000000 has more than 10000 digits"), err)
+	assert.ErrorIs(t, ErrOutOfRange, err)
+
+	_, err = mustParse("0.5").Pow(1_000_000_000_000, DefaultContext)
+	assert.ErrorIs(t, ErrOutOfRange, err)
+
+	p, err = mustParse("1").Pow(1_000_000_000_000, DefaultContext)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", p.String())
+
+	p, err = Decimal{}.Pow(1_000_000, DefaultContext)
+	assert.Nil(t, err)
+	assert.Equal(t, "0", p.String())
+
+	m, err := mustParse("10.5").Mod(mustParse("3"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1.5", m.String())
+
+	m, err = mustParse("-7").Mod(mustParse("2"))
+	assert.Nil(t, err)
+	assert.Equal(t, "-1", m.String())
+
+	_, err = a.Mod(Decimal{})
+	assert.ErrorIs(t, ErrDivisionByZero, err)
+}
+
+func TestDiv(t *testing.T) {
+	testCases := []struct {
+		a, b   string
+		ctx    Context
+		result string
+		err    error
+	}{
+		{"1", "4", DefaultContext, "0.25", nil},
+		{"10", "2", DefaultContext, "5", nil},
+		{"1", "3", DefaultContext, "0.3333333333333333", nil},
+		{"2", "3", DefaultContext, "0.6666666666666667", nil},
+		{"0.01", "0.0001", DefaultContext, "100", nil},
+		{"1", "3", Context{Scale: 2, Rounding: Up}, "0.34", nil},
+		{"-1", "3", Context{Scale: 2, Rounding: Ceiling}, "-0.33", nil},
+		{"-1", "3", Context{Scale: 2, Rounding: Floor}, "-0.34", nil},
+		{"2", "3", Context{Scale: 2, Rounding: Down}, "0.66", nil},
+		{"1", "0", DefaultContext, "0", errors.New("division by zero")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			actual, err := mustParse(tc.a).Div(mustParse(tc.b), tc.ctx)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual.String())
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	testCases := []struct {
+		input string
+		mode  RoundingMode
+		scale int
+		want  string
+	}{
+		{"2.345", HalfEven, 2, "2.34"},
+		{"2.355", HalfEven, 2, "2.36"},
+		{"2.3451", HalfEven, 2, "2.35"},
+		{"-2.345", HalfEven, 2, "-2.34"},
+		{"2.345", HalfUp, 2, "2.35"},
+		{"-2.345", HalfUp, 2, "-2.35"},
+		{"2.345", HalfDown, 2, "2.34"},
+		{"2.3451", HalfDown, 2, "2.35"},
+		{"2.349", Down, 2, "2.34"},
+		{"-2.349", Down, 2, "-2.34"},
+		{"2.341", Up, 2, "2.35"},
+		{"-2.341", Up, 2, "-2.35"},
+		{"2.341", Ceiling, 2, "2.35"},
+		{"-2.341", Ceiling, 2, "-2.34"},
+		{"2.349", Floor, 2, "2.34"},
+		{"-2.341", Floor, 2, "-2.35"},
+		{"1250", HalfEven, -2, "1200"},
+		{"1.5", HalfEven, 4, "1.5"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s_%d_%d", tc.input, tc.mode, tc.scale), func(t *testing.T) {
+			actual := mustParse(tc.input).Round(Context{Scale: tc.scale, Rounding: tc.mode})
+			assert.Equal(t, tc.want, actual.String())
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CoercionPolicy decides which implicit conversions are made when a value of one type is used as another
+type CoercionPolicy int
+
+const (
+	// CoercionLenient converts freely between types, numbers and strings such as "yes" can be used as bools and
+	// numeric strings as numbers. nil is treated as an empty string alongside a string. This is the default
+	CoercionLenient CoercionPolicy = iota
+	// CoercionStrict makes no implicit conversions, values must already have the type they are used as
+	CoercionStrict
+	// CoercionJS follows JavaScript, every value is truthy or falsy, strings are converted to numbers for arithmetic
+	// and comparison, adding a string concatenates and nil is "null" as a string or 0 as a number
+	CoercionJS
+)
+
+// String returns the name of the policy
+func (c CoercionPolicy) String() string {
+	switch c {
+	case CoercionLenient:
+		return "lenient"
+	case CoercionStrict:
+		return "strict"
+	case CoercionJS:
+		return "js"
+	}
+
+	return fmt.Sprintf("CoercionPolicy(%d)", int(c))
+}
+
+// ToBool converts the input value in to a bool following the policy
+func (c CoercionPolicy) ToBool(e any) (bool, error) {
+	switch c {
+	case CoercionLenient:
+		return ToBool(e)
+	case CoercionStrict:
+		if b, ok := e.(bool); ok {
+			return b, nil
+		}
+	case CoercionJS:
+		return truthy(e), nil
+	}
+
+	return false, fmt.Errorf("%w, invalid type: %T", ErrInvalidBool, e)
+}
+
+// ToFloat64 converts the input value in to a float64 following the policy
+func (c CoercionPolicy) ToFloat64(e any) (float64, error) {
+	switch c {
+	case CoercionLenient:
+		return ToFloat64(e)
+	case CoercionStrict:
+		if _, ok := e.(string); !ok {
+			return ToFloat64(e)
+		}
+	case CoercionJS:
+		return jsNumber(e)
+	}
+
+	return 0, fmt.Errorf("%w, invalid type: %T", ErrInvalidFloat, e)
+}
+
+// ToInt64 converts the input value in to an int64 following the policy, the value must be a whole number
+func (c CoercionPolicy) ToInt64(e any) (int64, error) {
+	switch c {
+	case CoercionLenient:
+		return ToInt64(e)
+	case CoercionStrict:
+		if _, ok := e.(string); !ok {
+			return ToInt64(e)
+		}
+	case CoercionJS:
+		switch e.(type) {
+		case nil, bool, string:
+			f, _ := jsNumber(e)
+			return floatToInt64(f)
+		}
+
+		return ToInt64(e)
+	}
+
+	return 0, fmt.Errorf("%w, invalid type: %T", ErrInvalidInt, e)
+}
+
+// ToString converts the input value in to a string following the policy
+func (c CoercionPolicy) ToString(e any) (string, error) {
+	switch c {
+	case CoercionLenient:
+		return ToString(e)
+	case CoercionStrict:
+		if s, ok := e.(string); ok {
+			return s, nil
+		}
+	case CoercionJS:
+		if e == nil {
+			return "null", nil
+		}
+
+		return ToString(e)
+	}
+
+	return "", fmt.Errorf("%w, invalid type: %T", ErrInvalidString, e)
+}
+
+// ToTime converts the input value in to a time following the policy, only strict refuses to parse strings
+func (c CoercionPolicy) ToTime(e any) (time.Time, error) {
+	if _, ok := e.(string); ok && c == CoercionStrict {
+		return time.Time{}, fmt.Errorf("%w, invalid type: %T", ErrInvalidTime, e)
+	}
+
+	return ToTime(e)
+}
+
+// ToDuration converts the input value in to a duration following the policy, only strict refuses to parse strings
+func (c CoercionPolicy) ToDuration(e any) (time.Duration, error) {
+	if _, ok := e.(string); ok && c == CoercionStrict {
+		return 0, fmt.Errorf("%w, invalid type: %T", ErrInvalidDuration, e)
+	}
+
+	return ToDuration(e)
+}
+
+// truthy checks whether the value is true in JavaScript. nil, false, 0, NaN and "" are false, anything else is true
+func truthy(e any) bool {
+	switch x := e.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != ""
+	case Number:
+		return x.Sign() != 0
+	case int, uint, uint8, uint16, uint32, uint64, int8, int16, int32, int64, float32, float64:
+		f, _ := ToFloat64(x)
+		return f != 0 && !math.IsNaN(f)
+	}
+
+	return true
+}
+
+// jsNumber converts the value as JavaScript's Number() would. Strings which aren't numbers become NaN
+func jsNumber(e any) (float64, error) {
+	switch x := e.(type) {
+	case nil:
+		return 0, nil
+	case bool:
+		if x {
+			return 1, nil
+		}
+
+		return 0, nil
+	case string:
+		s := strings.TrimSpace(x)
+		if s == "" {
+			return 0, nil
+		}
+
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return math.NaN(), nil
+		}
+
+		return f, nil
+	}
+
+	return ToFloat64(e)
+}
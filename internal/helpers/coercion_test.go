@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestCoercionToBool(t *testing.T) {
+	testCases := []struct {
+		c      CoercionPolicy
+		a      any
+		result bool
+		err    error
+	}{
+		{CoercionLenient, "yarp", true, nil},
+		{CoercionLenient, -1, false, nil},
+		{CoercionStrict, true, true, nil},
+		{CoercionStrict, "yes", false, errors.New("error parsing value as bool, invalid type: string")},
+		{CoercionStrict, 1, false, errors.New("error parsing value as bool, invalid type: int")},
+		{CoercionStrict, nil, false, errors.New("error parsing value as bool, invalid type: <nil>")},
+		{CoercionJS, nil, false, nil},
+		{CoercionJS, -1, true, nil},
+		{CoercionJS, 0, false, nil},
+		{CoercionJS, math.NaN(), false, nil},
+		{CoercionJS, "false", true, nil},
+		{CoercionJS, "", false, nil},
+		{CoercionJS, []any{}, true, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s_%T_%v", tc.c, tc.a, tc.a), func(t *testing.T) {
+			result, err := tc.c.ToBool(tc.a)
+			assert.Equal(t, tc.result, result)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestCoercionToFloat64(t *testing.T) {
+	testCases := []struct {
+		c      CoercionPolicy
+		a      any
+		result float64
+		err    error
+	}{
+		{CoercionLenient, "12", 12, nil},
+		{CoercionLenient, nil, 0, errors.New("error parsing value as float, invalid type: <nil>")},
+		{CoercionStrict, int64(12), 12, nil},
+		{CoercionStrict, "12", 0, errors.New("error parsing value as float, invalid type: string")},
+		{CoercionStrict, true, 0, errors.New("error parsing value as float, invalid type: bool")},
+		{CoercionJS, " 12 ", 12, nil},
+		{CoercionJS, "", 0, nil},
+		{CoercionJS, nil, 0, nil},
+		{CoercionJS, true, 1, nil},
+		{CoercionJS, []any{}, 0, errors.New("error parsing value as float, invalid type: []interface {}")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s_%T_%v", tc.c, tc.a, tc.a), func(t *testing.T) {
+			result, err := tc.c.ToFloat64(tc.a)
+			assert.Equal(t, tc.result, result)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+
+	f, err := CoercionJS.ToFloat64("abc")
+	assert.Nil(t, err)
+	assert.Equal(t, true, math.IsNaN(f))
+}
+
+func TestCoercionConversions(t *testing.T) {
+	s, err := CoercionJS.ToString(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "null", s)
+
+	s, err = CoercionLenient.ToString(12)
+	assert.Nil(t, err)
+	assert.Equal(t, "12", s)
+
+	_, err = CoercionStrict.ToString(12)
+	assert.ErrorEqual(t, errors.New("error converting value to string, invalid type: int"), err)
+
+	i, err := CoercionJS.ToInt64("12")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), i)
+
+	_, err = CoercionStrict.ToInt64("12")
+	assert.ErrorIs(t, ErrInvalidInt, err)
+
+	i, err = CoercionJS.ToInt64(int64(math.MaxInt64))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(math.MaxInt64), i)
+
+	_, err = CoercionStrict.ToTime("2024-01-01T00:00:00Z")
+	assert.ErrorIs(t, ErrInvalidTime, err)
+
+	d, err := CoercionLenient.ToDuration("1h")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Hour, d)
+
+	_, err = CoercionStrict.ToDuration("1h")
+	assert.ErrorIs(t, ErrInvalidDuration, err)
+}
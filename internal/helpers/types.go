@@ -3,18 +3,44 @@ package helpers
 
 import (
 	"fmt"
+	"math"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	// ErrInvalidFloat is returned when a values fails to parse as a float64
 	ErrInvalidFloat = ConstError("error parsing value as float")
 
+	// ErrInvalidInt is returned when a value fails to parse as an int64
+	ErrInvalidInt = ConstError("error parsing value as int")
+
+	// ErrInvalidString is returned when a value can't be used as a string
+	ErrInvalidString = ConstError("error converting value to string")
+
 	// ErrInvalidBool is returned when a value fails to parse as a bool
 	ErrInvalidBool = ConstError("error parsing value as bool")
+
+	// ErrInvalidTime is returned when a value fails to parse as a time
+	ErrInvalidTime = ConstError("error parsing value as time")
+
+	// ErrInvalidDuration is returned when a value fails to parse as a duration
+	ErrInvalidDuration = ConstError("error parsing value as duration")
+
+	// ErrDivisionByZero is returned when an exact number, such as an integer or decimal, is divided by zero
+	ErrDivisionByZero = ConstError("division by zero")
 )
 
+// Number is implemented by numeric types other than Go's own, such as exact decimals
+type Number interface {
+	Float64() float64
+	Int64() (int64, bool)
+	IsInteger() bool
+	Sign() int
+}
+
 // TypesMatch check if the types of the two values are the same
 func TypesMatch(a, b any) bool {
 	return fmt.Sprintf("%T", a) == fmt.Sprintf("%T", b)
@@ -54,11 +80,83 @@ func ToFloat64(input any) (float64, error) {
 		}
 
 		return ret, nil
+	case Number:
+		return x.Float64(), nil
 	}
 
 	return 0, fmt.Errorf("%w, invalid type: %T", ErrInvalidFloat, input)
 }
 
+// ToInt64 converts the input value in to an int64. Integers are converted exactly, other numbers and strings must
+// hold a whole number within range
+func ToInt64(e any) (int64, error) {
+	switch x := e.(type) {
+	case int:
+		return int64(x), nil
+	case int8:
+		return int64(x), nil
+	case int16:
+		return int64(x), nil
+	case int32:
+		return int64(x), nil
+	case int64:
+		return x, nil
+	case uint8:
+		return int64(x), nil
+	case uint16:
+		return int64(x), nil
+	case uint32:
+		return int64(x), nil
+	case uint, uint64:
+		u := reflect.ValueOf(x).Uint()
+		if u > math.MaxInt64 {
+			return 0, fmt.Errorf("%w, %v is out of range", ErrInvalidInt, x)
+		}
+
+		return int64(u), nil
+	case float32, float64:
+		f, _ := ToFloat64(x)
+		return floatToInt64(f)
+	case string:
+		if i, err := strconv.ParseInt(x, 10, 64); err == nil {
+			return i, nil
+		}
+
+		f, err := strconv.ParseFloat(x, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w, could not parse string '%s'", ErrInvalidInt, x)
+		}
+
+		return floatToInt64(f)
+	case Number:
+		i, ok := x.Int64()
+		if !x.IsInteger() {
+			return 0, fmt.Errorf("%w, %v is not a whole number", ErrInvalidInt, x)
+		}
+
+		if !ok {
+			return 0, fmt.Errorf("%w, %v is out of range", ErrInvalidInt, x)
+		}
+
+		return i, nil
+	}
+
+	return 0, fmt.Errorf("%w, invalid type: %T", ErrInvalidInt, e)
+}
+
+func floatToInt64(f float64) (int64, error) {
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("%w, %v is not a whole number", ErrInvalidInt, f)
+	}
+
+	// 2^63 is the first float64 too large to fit
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w, %v is out of range", ErrInvalidInt, f)
+	}
+
+	return int64(f), nil
+}
+
 // ToBool converts the input value in to a bool.
 //
 // - If the type is a number then any value over 0 will return true
@@ -75,6 +173,8 @@ func ToBool(e any) (bool, error) {
 		}
 
 		return i > 0, nil
+	case Number:
+		return x.Sign() > 0, nil
 	case string:
 		switch strings.ToLower(x) {
 		case "yes", "true", "y", "1", "yarp":
@@ -93,7 +193,57 @@ func ToBool(e any) (bool, error) {
 	return false, fmt.Errorf("%w, invalid type: %T", ErrInvalidBool, e)
 }
 
-// ToString converts the input to a string
+// ToString converts the input to a string, times are formatted as RFC3339
 func ToString(e any) (string, error) {
+	if t, ok := e.(time.Time); ok {
+		return t.Format(time.RFC3339Nano), nil
+	}
+
 	return fmt.Sprint(e), nil
 }
+
+// ToTime converts the input to a time, parsing strings as RFC3339
+func ToTime(e any) (time.Time, error) {
+	switch x := e.(type) {
+	case time.Time:
+		return x, nil
+	case *time.Time:
+		if x != nil {
+			return *x, nil
+		}
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, x)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w, could not parse string '%s'", ErrInvalidTime, x)
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w, invalid type: %T", ErrInvalidTime, e)
+}
+
+// ToDuration converts the input to a duration, parsing strings such as "1h15m". Numbers are treated as nanoseconds,
+// in the same way as time.Duration
+func ToDuration(e any) (time.Duration, error) {
+	switch x := e.(type) {
+	case time.Duration:
+		return x, nil
+	case string:
+		d, err := time.ParseDuration(x)
+		if err != nil {
+			return 0, fmt.Errorf("%w, could not parse string '%s'", ErrInvalidDuration, x)
+		}
+
+		return d, nil
+	case int, uint, uint8, uint16, uint32, uint64, int8, int16, int32, int64, float32, float64:
+		f, err := ToFloat64(x)
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(f), nil
+	}
+
+	return 0, fmt.Errorf("%w, invalid type: %T", ErrInvalidDuration, e)
+}
@@ -3,7 +3,9 @@ package helpers
 import (
 	"errors"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/scottkgregory/parsley/internal/assert"
 )
@@ -77,6 +79,43 @@ func TestToFloat64(t *testing.T) {
 	}
 }
 
+func TestToInt64(t *testing.T) {
+	testCases := []struct {
+		a      any
+		result int64
+		err    error
+	}{
+		{int(12), 12, nil},
+		{int8(-12), -12, nil},
+		{int32(12), 12, nil},
+		{int64(9007199254740993), 9007199254740993, nil},
+		{uint8(12), 12, nil},
+		{uint64(math.MaxInt64), math.MaxInt64, nil},
+		{float64(12), 12, nil},
+		{float32(-3), -3, nil},
+		{"9007199254740993", 9007199254740993, nil},
+		{"12.0", 12, nil},
+
+		{uint64(math.MaxUint64), 0, errors.New("error parsing value as int, 18446744073709551615 is out of range")},
+		{float64(12.5), 0, errors.New("error parsing value as int, 12.5 is not a whole number")},
+		{float64(1e19), 0, errors.New("error parsing value as int, 1e+19 is out of range")},
+		{math.NaN(), 0, errors.New("error parsing value as int, NaN is not a whole number")},
+		{"12.5", 0, errors.New("error parsing value as int, 12.5 is not a whole number")},
+		{"NOT_A_NUMBER", 0, errors.New("error parsing value as int, could not parse string 'NOT_A_NUMBER'")},
+		{true, 0, errors.New("error parsing value as int, invalid type: bool")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%T_%v", tc.a, tc.a), func(t *testing.T) {
+			result, err := ToInt64(tc.a)
+			assert.Equal(t, tc.result, result)
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrInvalidInt, err)
+			}
+		})
+	}
+}
+
 func TestToBool(t *testing.T) {
 	testCases := []struct {
 		a      any
@@ -143,3 +182,54 @@ func TestToBool(t *testing.T) {
 		})
 	}
 }
+
+func TestToTime(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	testCases := []struct {
+		a      any
+		result time.Time
+		err    error
+	}{
+		{ts, ts, nil},
+		{&ts, ts, nil},
+		{"2024-03-01T12:30:00Z", ts, nil},
+		{"2024-03-01", time.Time{}, errors.New("error parsing value as time, could not parse string '2024-03-01'")},
+		{(*time.Time)(nil), time.Time{}, errors.New("error parsing value as time, invalid type: *time.Time")},
+		{12, time.Time{}, errors.New("error parsing value as time, invalid type: int")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%T_%v", tc.a, tc.a), func(t *testing.T) {
+			result, err := ToTime(tc.a)
+			assert.Equal(t, tc.result, result)
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrInvalidTime, err)
+			}
+		})
+	}
+}
+
+func TestToDuration(t *testing.T) {
+	testCases := []struct {
+		a      any
+		result time.Duration
+		err    error
+	}{
+		{time.Minute, time.Minute, nil},
+		{"1h15m", 75 * time.Minute, nil},
+		{int64(1000), time.Microsecond, nil},
+		{float64(1e9), time.Second, nil},
+		{"soon", 0, errors.New("error parsing value as duration, could not parse string 'soon'")},
+		{true, 0, errors.New("error parsing value as duration, invalid type: bool")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%T_%v", tc.a, tc.a), func(t *testing.T) {
+			result, err := ToDuration(tc.a)
+			assert.Equal(t, tc.result, result)
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrInvalidDuration, err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 // ErrComparisonFailed is returned when the comparison of two values fails
@@ -13,32 +14,41 @@ const ErrComparisonFailed = helpers.ConstError("error running comparison")
 
 // BinaryNode is a node that has both a left and right side
 type BinaryNode struct {
-	Left  Node
-	Right Node
-	op    string
+	Left      Node
+	Right     Node
+	Semantics *Semantics
+	op        string
 }
 
-var _ Node = &BinaryNode{}
+var (
+	_ TypedNode  = &BinaryNode{}
+	_ ScopedNode = &BinaryNode{}
+)
 
 // NewBinaryNode creates a new binary node
 func NewBinaryNode(left, right Node, op string) *BinaryNode {
-	return &BinaryNode{left, right, op}
+	return &BinaryNode{Left: left, Right: right, op: op}
 }
 
 // Eval runs the appropriate logic to evaluate the node and produce a single result
 func (n *BinaryNode) Eval(data map[string]any) (any, error) {
+	return n.EvalIn(NewScope(data))
+}
+
+// EvalIn evaluates the node in the scope
+func (n *BinaryNode) EvalIn(scope *Scope) (any, error) {
 	// Evaluate both sides
-	leftVal, leftErr := n.Left.Eval(data)
+	leftVal, leftErr := EvalIn(n.Left, scope)
 	if leftErr != nil {
 		return nil, fmt.Errorf("%w, left side error: %w", ErrNodeEvalFailed, leftErr)
 	}
 
-	rightVal, rightErr := n.Right.Eval(data)
+	rightVal, rightErr := EvalIn(n.Right, scope)
 	if rightErr != nil {
 		return nil, fmt.Errorf("%w, right side error: %w", ErrNodeEvalFailed, rightErr)
 	}
 
-	ret, err := Calculate(n.op, leftVal, rightVal)
+	ret, err := n.Semantics.Calculate(n.op, leftVal, rightVal)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNodeEvalFailed, err)
 	}
@@ -46,46 +56,94 @@ func (n *BinaryNode) Eval(data map[string]any) (any, error) {
 	return ret, nil
 }
 
+// Type returns the type of the result, checking both sides are valid for the operation
+func (n *BinaryNode) Type(scope types.Type) (types.Type, error) {
+	left, err := TypeOf(n.Left, scope)
+	if err != nil {
+		return types.Any, err
+	}
+
+	right, err := TypeOf(n.Right, scope)
+	if err != nil {
+		return types.Any, err
+	}
+
+	return CalculateType(n.op, left, right)
+}
+
 // String returns the string representation
 func (n *BinaryNode) String() string {
 	f := "%s%s%s"
-	if n.op == "<" || n.op == ">" || n.op == "==" || n.op == "||" || n.op == "&&" {
+	if n.op == "<" || n.op == ">" || n.op == "==" || n.op == "||" || n.op == "&&" || n.op == "=~" || n.op == "!~" || n.op == "in" {
 		f = "%s %s %s"
 	}
 
 	return fmt.Sprintf(f, n.Left.String(), n.op, n.Right.String())
 }
 
-// Calculate performs the provided operation on the given values
+// Calculate performs the provided operation on the given values using the default semantics
 func Calculate(op string, a, b any) (any, error) {
+	return (*Semantics)(nil).Calculate(op, a, b)
+}
+
+// Calculate performs the provided operation on the given values
+func (s *Semantics) Calculate(op string, a, b any) (any, error) {
 	if op == "||" || op == "&&" {
-		x, err := helpers.ToBool(a)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %w", ErrComparisonFailed, err)
-		}
+		return s.logical(op, a, b)
+	}
 
-		y, err := helpers.ToBool(b)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %w", ErrComparisonFailed, err)
-		}
+	// Anything else involving an unknown value is unknown
+	if s.nulls(a, b) {
+		return nil, nil
+	}
 
-		if op == "||" {
-			return x || y, nil
-		}
+	// Two nils are equal, matching Equal as used by in and unique
+	if op == "==" && IsNull(a) && IsNull(b) {
+		return true, nil
+	}
+
+	coercion := s.coercion()
+	if op == "=~" || op == "!~" {
+		return match(op, a, b, coercion)
+	}
+
+	if op == "in" {
+		return in(a, b)
+	}
+
+	if isTemporal(a, b) {
+		return calculateTime(op, a, b, coercion)
+	}
 
-		return x && y, nil
+	if isStructured(a) || isStructured(b) {
+		return calculateStructured(op, a, b)
 	}
 
 	x, aOk := a.(string)
 	y, bOk := b.(string)
 
-	if aOk && b == nil {
-		b = ""
-		bOk = true
+	if coercion == helpers.CoercionJS {
+		// nil only equals nil, and adding a string concatenates. Anything else involving a string is numeric
+		if op == "==" && (a == nil || b == nil) {
+			return a == nil && b == nil, nil
+		}
+
+		if op == "+" && (aOk || bOk) {
+			x, _ = coercion.ToString(a)
+			y, _ = coercion.ToString(b)
+			return x + y, nil
+		}
 	}
-	if bOk && a == nil {
-		a = ""
-		aOk = true
+
+	if coercion == helpers.CoercionLenient {
+		if aOk && b == nil {
+			b = ""
+			bOk = true
+		}
+		if bOk && a == nil {
+			a = ""
+			aOk = true
+		}
 	}
 
 	if aOk && bOk {
@@ -101,16 +159,24 @@ func Calculate(op string, a, b any) (any, error) {
 		}
 	}
 
-	if aOk || bOk {
+	if (aOk || bOk) && coercion != helpers.CoercionJS {
 		return nil, fmt.Errorf("%w: only one side of comparison was a string: %T %T", ErrComparisonFailed, a, b)
 	}
 
-	aa, aErr := helpers.ToFloat64(a)
+	if ctx, ok := s.DecimalContext(); ok || isDecimal(a) || isDecimal(b) {
+		return calculateDecimal(op, a, b, ctx)
+	}
+
+	if s.integers(a, b) {
+		return calculateInteger(op, a, b)
+	}
+
+	aa, aErr := coercion.ToFloat64(a)
 	if aErr != nil {
 		return nil, fmt.Errorf("%w: error in left side: %w", ErrComparisonFailed, aErr)
 	}
 
-	bb, bErr := helpers.ToFloat64(b)
+	bb, bErr := coercion.ToFloat64(b)
 	if bErr != nil {
 		return nil, fmt.Errorf("%w: error in right side: %w", ErrComparisonFailed, bErr)
 	}
@@ -130,9 +196,96 @@ func Calculate(op string, a, b any) (any, error) {
 		return aa * bb, nil
 	case "-":
 		return aa - bb, nil
+	case "%":
+		return math.Mod(aa, bb), nil
 	case "^":
 		return math.Pow(aa, bb), nil
 	}
 
 	return nil, fmt.Errorf("%w: unrecognised op: %s", ErrComparisonFailed, string(op))
 }
+
+// CalculateType works out the type produced by performing the operation on values of the given types. It mirrors
+// the rules applied by Calculate, but is stricter as strings are never assumed to hold numbers
+func CalculateType(op string, a, b types.Type) (types.Type, error) {
+	if op == "||" || op == "&&" {
+		for _, t := range []types.Type{a, b} {
+			if !t.Is(types.KindAny, types.KindBool, types.KindNumber, types.KindString) {
+				return types.Any, fmt.Errorf("%w: cannot use %s as bool", types.ErrTypeCheckFailed, t)
+			}
+		}
+
+		return types.Bool, nil
+	}
+
+	if op == "=~" || op == "!~" {
+		if !a.Is(types.KindAny, types.KindNull, types.KindString) {
+			return types.Any, fmt.Errorf("%w: cannot match %s against a regular expression", types.ErrTypeCheckFailed, a)
+		}
+
+		if !b.AssignableTo(types.Regexp) {
+			return types.Any, fmt.Errorf("%w: cannot use %s as a regular expression", types.ErrTypeCheckFailed, b)
+		}
+
+		return types.Bool, nil
+	}
+
+	if op == "in" {
+		return inType(a, b)
+	}
+
+	comparison := op == "<" || op == ">" || op == "=="
+	arithmetic := op == "+" || op == "-" || op == "*" || op == "/" || op == "%" || op == "^"
+	if !comparison && !arithmetic {
+		return types.Any, fmt.Errorf("%w: unrecognised op: %s", types.ErrTypeCheckFailed, op)
+	}
+
+	if a.Is(types.KindTime, types.KindDuration) || b.Is(types.KindTime, types.KindDuration) {
+		return calculateTimeType(op, a, b)
+	}
+
+	if a.Is(types.KindList, types.KindObject) || b.Is(types.KindList, types.KindObject) {
+		return calculateStructuredType(op, a, b)
+	}
+
+	// Nothing more can be said until evaluation
+	if a.Is(types.KindAny) || b.Is(types.KindAny) {
+		switch {
+		case comparison:
+			return types.Bool, nil
+		case op == "+":
+			return types.Any, nil
+		}
+
+		return types.Number, nil
+	}
+
+	// nil is treated as an empty string when used alongside a string
+	aStr := a.Is(types.KindString) || (a.Is(types.KindNull) && b.Is(types.KindString))
+	bStr := b.Is(types.KindString) || (b.Is(types.KindNull) && a.Is(types.KindString))
+
+	if aStr && bStr {
+		switch op {
+		case "+":
+			return types.String, nil
+		case "<", ">", "==":
+			return types.Bool, nil
+		}
+
+		return types.Any, fmt.Errorf("%w: unsupported op for strings: %s", types.ErrTypeCheckFailed, op)
+	}
+
+	if aStr || bStr {
+		return types.Any, fmt.Errorf("%w: only one side of comparison was a string: %s %s", types.ErrTypeCheckFailed, a, b)
+	}
+
+	if !a.Is(types.KindNumber) || !b.Is(types.KindNumber) {
+		return types.Any, fmt.Errorf("%w: unsupported types for %s: %s %s", types.ErrTypeCheckFailed, op, a, b)
+	}
+
+	if comparison {
+		return types.Bool, nil
+	}
+
+	return types.Number, nil
+}
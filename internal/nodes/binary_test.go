@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 func TestBinaryNode(t *testing.T) {
@@ -136,6 +137,8 @@ func TestComparison(t *testing.T) {
 		{comp: "==", a: 2, b: "a", result: nil, err: errors.New("error running comparison: only one side of comparison was a string: int string")},
 		{comp: "==", a: 1, b: 2, result: false, err: nil},
 		{comp: "==", a: 1, b: 1, result: true, err: nil},
+		{comp: "==", a: nil, b: nil, result: true, err: nil},
+		{comp: "==", a: nil, b: (*int)(nil), result: true, err: nil},
 
 		{comp: "£", a: 1, b: 1, result: nil, err: errors.New("error running comparison: unrecognised op: £")},
 	}
@@ -168,6 +171,8 @@ func TestComparisonString(t *testing.T) {
 		{comp: "==", result: "2 == 6"},
 		{comp: "||", result: "2 || 6"},
 		{comp: "&&", result: "2 && 6"},
+		{comp: "=~", result: "2 =~ 6"},
+		{comp: "!~", result: "2 !~ 6"},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.comp, func(t *testing.T) {
@@ -176,3 +181,65 @@ func TestComparisonString(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateType(t *testing.T) {
+	testCases := []struct {
+		comp   string
+		a, b   types.Type
+		result types.Type
+		err    error
+	}{
+		{comp: "+", a: types.Number, b: types.Number, result: types.Number},
+		{comp: "+", a: types.String, b: types.String, result: types.String},
+		{comp: "+", a: types.String, b: types.Null, result: types.String},
+		{comp: "+", a: types.Any, b: types.String, result: types.Any},
+		{comp: "-", a: types.Any, b: types.Number, result: types.Number},
+		{comp: "^", a: types.Number, b: types.Number, result: types.Number},
+		{comp: "<", a: types.String, b: types.String, result: types.Bool},
+		{comp: ">", a: types.Number, b: types.Any, result: types.Bool},
+		{comp: "==", a: types.Null, b: types.String, result: types.Bool},
+		{comp: "&&", a: types.Bool, b: types.Any, result: types.Bool},
+		{comp: "||", a: types.Number, b: types.String, result: types.Bool},
+
+		{comp: ">", a: types.String, b: types.Number, result: types.Any, err: errors.New("type check failed: only one side of comparison was a string: string number")},
+		{comp: "-", a: types.String, b: types.String, result: types.Any, err: errors.New("type check failed: unsupported op for strings: -")},
+		{comp: "*", a: types.Bool, b: types.Number, result: types.Any, err: errors.New("type check failed: unsupported types for *: bool number")},
+		{comp: "==", a: types.Null, b: types.Number, result: types.Any, err: errors.New("type check failed: unsupported types for ==: null number")},
+		{comp: "&&", a: types.ListOf(types.Any), b: types.Bool, result: types.Any, err: errors.New("type check failed: cannot use list<any> as bool")},
+		{comp: "£", a: types.Number, b: types.Number, result: types.Any, err: errors.New("type check failed: unrecognised op: £")},
+
+		{comp: "=~", a: types.String, b: types.String, result: types.Bool},
+		{comp: "!~", a: types.Null, b: types.Regexp, result: types.Bool},
+		{comp: "=~", a: types.Any, b: types.Any, result: types.Bool},
+		{comp: "=~", a: types.Number, b: types.String, result: types.Any, err: errors.New("type check failed: cannot match number against a regular expression")},
+		{comp: "=~", a: types.String, b: types.Number, result: types.Any, err: errors.New("type check failed: cannot use number as a regular expression")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s%s%s", tc.a, tc.comp, tc.b), func(t *testing.T) {
+			actual, err := CalculateType(tc.comp, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result.String(), actual.String())
+			if tc.err != nil {
+				assert.ErrorIs(t, types.ErrTypeCheckFailed, err)
+			}
+		})
+	}
+}
+
+func TestBinaryNodeType(t *testing.T) {
+	scope := types.ObjectOf(map[string]types.Type{"name": types.String})
+
+	actual, err := NewBinaryNode(NewVariableNode("name"), NewStringNode("foo"), "==").Type(scope)
+	assert.Nil(t, err)
+	assert.Equal(t, "bool", actual.String())
+
+	_, err = NewBinaryNode(NewVariableNode("nme"), NewStringNode("foo"), "==").Type(scope)
+	assert.ErrorEqual(t, errors.New("type check failed: unknown field nme in nme"), err)
+
+	_, err = NewBinaryNode(NewNumberNode(1), NewVariableNode("nme"), "==").Type(scope)
+	assert.ErrorEqual(t, errors.New("type check failed: unknown field nme in nme"), err)
+
+	actual, err = NewBinaryNode(NewMockNode(nil, 1, nil, "1"), NewNumberNode(1), "-").Type(scope)
+	assert.Nil(t, err)
+	assert.Equal(t, "number", actual.String())
+}
@@ -0,0 +1,353 @@
+package nodes
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/decimal"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+var (
+	timeType    = reflect.TypeFor[time.Time]()
+	decimalType = reflect.TypeFor[decimal.Decimal]()
+)
+
+// Equal checks whether two values are structurally equal. Numbers are compared by value whatever their Go type, lists
+// and arrays item by item, and maps and structs field by field. Struct fields are named by their json tags, so a
+// struct equals the map it would be decoded from. Values of different kinds are never equal
+func Equal(a, b any) bool {
+	return equal(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// Compare orders two values, returning a negative number when a is less than b, zero when they are equal and a
+// positive number when a is greater. Numbers, strings, times and lists of them can be ordered, lists are compared
+// item by item with shorter lists first. Other values return an error
+func Compare(a, b any) (int, error) {
+	return compare(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// Field returns the named field of a map or struct
+func Field(v any, name string) (any, bool) {
+	f, ok := fields(indirect(reflect.ValueOf(v)))[name]
+	if !ok || !f.CanInterface() {
+		return nil, false
+	}
+
+	return f.Interface(), true
+}
+
+// isStructured checks whether the value is a list, map or struct, other than a time
+func isStructured(v any) bool {
+	rv := indirect(reflect.ValueOf(v))
+	switch rv.Kind() { //nolint:exhaustive // Everything else is a scalar
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	case reflect.Struct:
+		return rv.Type() != timeType && rv.Type() != decimalType
+	}
+
+	return false
+}
+
+// calculateStructured performs the operation where at least one side is a list, map or struct
+func calculateStructured(op string, a, b any) (any, error) {
+	switch op {
+	case "==":
+		return Equal(a, b), nil
+	case "<", ">":
+		c, err := Compare(a, b)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrComparisonFailed, err)
+		}
+
+		if op == "<" {
+			return c < 0, nil
+		}
+
+		return c > 0, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported op for %T and %T: %s", ErrComparisonFailed, a, b, op)
+}
+
+// in checks whether a is an item of the list b, a field of the object b or a substring of the string b
+func in(a, b any) (any, error) {
+	rb := indirect(reflect.ValueOf(b))
+	switch rb.Kind() { //nolint:exhaustive // Only containers can be looked in
+	case reflect.Invalid:
+		return false, nil
+	case reflect.Slice, reflect.Array:
+		ra := reflect.ValueOf(a)
+		for i := range rb.Len() {
+			if equal(ra, rb.Index(i)) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case reflect.String, reflect.Map, reflect.Struct:
+		name, ok := a.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a string to look for in %T, got %T", ErrComparisonFailed, b, a)
+		}
+
+		if rb.Kind() == reflect.String {
+			return strings.Contains(rb.String(), name), nil
+		}
+
+		_, found := fields(rb)[name]
+		return found, nil
+	}
+
+	return nil, fmt.Errorf("%w: cannot look for a value in %T", ErrComparisonFailed, b)
+}
+
+// calculateStructuredType works out the type produced by an operation where at least one side is a list or object
+func calculateStructuredType(op string, a, b types.Type) (types.Type, error) {
+	switch op {
+	case "==":
+		return types.Bool, nil
+	case "<", ">":
+		if a.Is(types.KindAny, types.KindList) && b.Is(types.KindAny, types.KindList) {
+			return types.Bool, nil
+		}
+
+		return types.Any, fmt.Errorf("%w: cannot order %s and %s", types.ErrTypeCheckFailed, a, b)
+	}
+
+	return types.Any, fmt.Errorf("%w: unsupported op for %s and %s: %s", types.ErrTypeCheckFailed, a, b, op)
+}
+
+// inType checks the types of both sides of an in operation
+func inType(a, b types.Type) (types.Type, error) {
+	switch {
+	case b.Is(types.KindAny, types.KindNull, types.KindList):
+	case b.Is(types.KindString, types.KindObject):
+		if !a.Is(types.KindAny, types.KindString) {
+			return types.Any, fmt.Errorf("%w: expected a string to look for in %s, got %s", types.ErrTypeCheckFailed, b, a)
+		}
+	default:
+		return types.Any, fmt.Errorf("%w: cannot look for a value in %s", types.ErrTypeCheckFailed, b)
+	}
+
+	return types.Bool, nil
+}
+
+// indirect follows pointers and interfaces to the value they hold, nil pointers become the invalid value
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func equal(a, b reflect.Value) bool {
+	a, b = indirect(a), indirect(b)
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+
+	if a.Type() == decimalType || b.Type() == decimalType {
+		x, y, ok := decimals(a, b)
+		return ok && x.Cmp(y) == 0
+	}
+
+	if isNumber(a) || isNumber(b) {
+		return isNumber(a) && isNumber(b) && compareNumbers(a, b) == 0
+	}
+
+	if a.Type() == timeType || b.Type() == timeType {
+		return a.Type() == b.Type() && a.CanInterface() && b.CanInterface() && a.Interface().(time.Time).Equal(b.Interface().(time.Time))
+	}
+
+	switch a.Kind() { //nolint:exhaustive // Anything else falls back to reflect.DeepEqual
+	case reflect.String:
+		return b.Kind() == reflect.String && a.String() == b.String()
+	case reflect.Bool:
+		return b.Kind() == reflect.Bool && a.Bool() == b.Bool()
+	case reflect.Slice, reflect.Array:
+		if !isList(b) || a.Len() != b.Len() {
+			return false
+		}
+
+		for i := range a.Len() {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Map, reflect.Struct:
+		if b.Kind() != reflect.Map && b.Kind() != reflect.Struct {
+			return false
+		}
+
+		x, y := fields(a), fields(b)
+		if len(x) != len(y) {
+			return false
+		}
+
+		for name, f := range x {
+			g, ok := y[name]
+			if !ok || !equal(f, g) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return a.CanInterface() && b.CanInterface() && reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func compare(a, b reflect.Value) (int, error) {
+	a, b = indirect(a), indirect(b)
+
+	if x, y, ok := decimals(a, b); ok {
+		return x.Cmp(y), nil
+	}
+
+	switch {
+	case isNumber(a) && isNumber(b):
+		return compareNumbers(a, b), nil
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), nil
+	case a.IsValid() && b.IsValid() && a.Type() == timeType && b.Type() == timeType && a.CanInterface() && b.CanInterface():
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), nil
+	case isList(a) && isList(b):
+		for i := range min(a.Len(), b.Len()) {
+			c, err := compare(a.Index(i), b.Index(i))
+			if err != nil || c != 0 {
+				return c, err
+			}
+		}
+
+		return cmp.Compare(a.Len(), b.Len()), nil
+	}
+
+	return 0, fmt.Errorf("cannot order %s and %s", typeName(a), typeName(b))
+}
+
+func typeName(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+
+	return v.Type().String()
+}
+
+func isList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+func isNumber(v reflect.Value) bool {
+	return v.CanInt() || v.CanUint() || v.CanFloat()
+}
+
+// compareNumbers compares numbers of any type, integers are compared exactly
+func compareNumbers(a, b reflect.Value) int {
+	switch {
+	case a.CanInt() && b.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanInt() && b.CanUint():
+		if a.Int() < 0 {
+			return -1
+		}
+
+		return cmp.Compare(uint64(a.Int()), b.Uint())
+	case a.CanUint() && b.CanInt():
+		return -compareNumbers(b, a)
+	}
+
+	return cmp.Compare(toFloat(a), toFloat(b))
+}
+
+// decimals converts both values to decimals when at least one is a decimal and the other is a number
+func decimals(a, b reflect.Value) (decimal.Decimal, decimal.Decimal, bool) {
+	if !a.IsValid() || !b.IsValid() || (a.Type() != decimalType && b.Type() != decimalType) {
+		return decimal.Decimal{}, decimal.Decimal{}, false
+	}
+
+	x, okA := toDecimal(a)
+	y, okB := toDecimal(b)
+
+	return x, y, okA && okB
+}
+
+func toDecimal(v reflect.Value) (decimal.Decimal, bool) {
+	switch {
+	case v.Type() == decimalType && v.CanInterface():
+		return v.Interface().(decimal.Decimal), true
+	case v.CanInt():
+		return decimal.FromInt(v.Int()), true
+	case v.CanUint():
+		return decimal.FromUint(v.Uint()), true
+	case v.CanFloat():
+		d, err := decimal.FromFloat(v.Float())
+		return d, err == nil
+	}
+
+	return decimal.Decimal{}, false
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	}
+
+	return v.Float()
+}
+
+// fields returns the fields of a map or struct by name, anything else has no fields
+func fields(v reflect.Value) map[string]reflect.Value {
+	ret := map[string]reflect.Value{}
+	switch v.Kind() { //nolint:exhaustive // Only maps and structs have fields
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			ret[fmt.Sprint(iter.Key().Interface())] = iter.Value()
+		}
+	case reflect.Struct:
+		structFields(v, ret)
+	}
+
+	return ret
+}
+
+// structFields adds the exported fields of the struct, promoting those of untagged embedded structs as encoding/json does
+func structFields(v reflect.Value, ret map[string]reflect.Value) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, tagged := types.FieldName(f)
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && !tagged {
+			embedded := indirect(v.Field(i))
+			if embedded.Kind() == reflect.Struct {
+				structFields(embedded, ret)
+				continue
+			}
+		}
+
+		if f.IsExported() {
+			ret[name] = v.Field(i)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package nodes
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+type equalPoint struct {
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Label  string `json:"-"`
+	hidden string
+}
+
+func TestEqual(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	three := 3
+
+	testCases := []struct {
+		a, b   any
+		result bool
+	}{
+		{3, float64(3), true},
+		{int64(3), uint8(3), true},
+		{&three, 3.0, true},
+		{-1, uint64(18446744073709551615), false},
+		{int64(9007199254740993), int64(9007199254740992), false},
+		{"a", "a", true},
+		{"3", 3, false},
+		{true, true, true},
+		{true, 1, false},
+		{nil, nil, true},
+		{nil, "", false},
+		{(*int)(nil), nil, true},
+		{[]any{1, "a"}, []any{float64(1), "a"}, true},
+		{[]int{1, 2}, []float64{1, 2}, true},
+		{[]int{1, 2}, [2]int{1, 2}, true},
+		{[]int{1, 2}, []int{2, 1}, false},
+		{[]int{1, 2}, []int{1, 2, 3}, false},
+		{[]int{}, []any(nil), true},
+		{[]any{[]any{1}}, []any{[]int{1}}, true},
+		{map[string]any{"a": 1, "b": []any{2}}, map[string]any{"a": float64(1), "b": []int{2}}, true},
+		{map[string]any{"a": 1}, map[string]any{"a": 1, "b": 2}, false},
+		{map[string]any{"a": 1}, map[string]any{"b": 1}, false},
+		{map[string]int{"a": 1}, []int{1}, false},
+		{equalPoint{X: 1, Y: 2, Label: "p", hidden: "h"}, map[string]any{"x": float64(1), "y": float64(2)}, true},
+		{&equalPoint{X: 1, Y: 2}, equalPoint{X: 1, Y: 2, Label: "q"}, true},
+		{equalPoint{X: 1, Y: 2}, map[string]any{"x": 1, "y": 3}, false},
+		{now, now.In(time.FixedZone("X", 3600)), true},
+		{now, now.Format(time.RFC3339), false},
+		{time.Second, 1000000000, true},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v==%v", tc.a, tc.b), func(t *testing.T) {
+			assert.Equal(t, tc.result, Equal(tc.a, tc.b))
+			assert.Equal(t, tc.result, Equal(tc.b, tc.a))
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		a, b   any
+		result int
+		err    error
+	}{
+		{1, 2.5, -1, nil},
+		{uint64(18446744073709551615), -1, 1, nil},
+		{int64(9007199254740993), int64(9007199254740992), 1, nil},
+		{"b", "a", 1, nil},
+		{now, now.Add(time.Second), -1, nil},
+		{[]int{1, 2}, []any{1, 2}, 0, nil},
+		{[]int{1, 2}, []int{1, 3}, -1, nil},
+		{[]int{1, 2}, []int{1}, 1, nil},
+		{[]string{"b"}, []string{"a", "z"}, 1, nil},
+		{[]any{1}, []any{"a"}, 0, errors.New("cannot order int and string")},
+		{map[string]any{}, map[string]any{}, 0, errors.New("cannot order map[string]interface {} and map[string]interface {}")},
+		{nil, 1, 0, errors.New("cannot order <nil> and int")},
+		{true, false, 0, errors.New("cannot order bool and bool")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v<%v", tc.a, tc.b), func(t *testing.T) {
+			actual, err := Compare(tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
+
+func TestCalculateStructured(t *testing.T) {
+	testCases := []struct {
+		op     string
+		a, b   any
+		result any
+		err    error
+	}{
+		{"==", []any{1, 2}, []int{1, 2}, true, nil},
+		{"==", []any{1, 2}, 1, false, nil},
+		{"==", map[string]any{"a": 1}, map[string]any{"a": 1}, true, nil},
+		{"<", []any{1, 2}, []any{1, 3}, true, nil},
+		{">", []any{1, 2}, []any{1, 3}, false, nil},
+		{"<", map[string]any{}, map[string]any{}, nil, errors.New("error running comparison: cannot order map[string]interface {} and map[string]interface {}")},
+		{"+", []any{1}, []any{2}, nil, errors.New("error running comparison: unsupported op for []interface {} and []interface {}: +")},
+		{"in", 2, []any{1, 2}, true, nil},
+		{"in", []int{2}, []any{[]any{2}}, true, nil},
+		{"in", "3", []any{3}, false, nil},
+		{"in", "a", map[string]any{"a": nil}, true, nil},
+		{"in", "x", equalPoint{}, true, nil},
+		{"in", "Label", equalPoint{}, false, nil},
+		{"in", "ell", "hello", true, nil},
+		{"in", 1, nil, false, nil},
+		{"in", 1, "hello", nil, errors.New("error running comparison: expected a string to look for in string, got int")},
+		{"in", 1, 2, nil, errors.New("error running comparison: cannot look for a value in int")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v%s%v", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := Calculate(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrComparisonFailed, err)
+			}
+		})
+	}
+}
+
+func TestCalculateStructuredType(t *testing.T) {
+	testCases := []struct {
+		op     string
+		a, b   types.Type
+		result string
+		err    error
+	}{
+		{"==", types.ListOf(types.Number), types.ListOf(types.String), "bool", nil},
+		{"==", types.ObjectOf(nil), types.Number, "bool", nil},
+		{"<", types.ListOf(types.Number), types.Any, "bool", nil},
+		{"<", types.ObjectOf(nil), types.ObjectOf(nil), "any", errors.New("type check failed: cannot order object and object")},
+		{"+", types.ListOf(types.Number), types.ListOf(types.Number), "any", errors.New("type check failed: unsupported op for list<number> and list<number>: +")},
+		{"in", types.Number, types.ListOf(types.Number), "bool", nil},
+		{"in", types.String, types.MapOf(types.Number), "bool", nil},
+		{"in", types.Number, types.String, "any", errors.New("type check failed: expected a string to look for in string, got number")},
+		{"in", types.Number, types.Number, "any", errors.New("type check failed: cannot look for a value in number")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s%s%s", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := CalculateType(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual.String())
+		})
+	}
+}
+
+func TestField(t *testing.T) {
+	v, ok := Field(map[string]any{"a": 1}, "a")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 1, v)
+
+	v, ok = Field(&equalPoint{X: 2}, "x")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 2, v)
+
+	_, ok = Field(equalPoint{}, "hidden")
+	assert.Equal(t, false, ok)
+
+	_, ok = Field("a", "a")
+	assert.Equal(t, false, ok)
+}
@@ -3,6 +3,8 @@ package nodes
 import (
 	"fmt"
 	"strings"
+
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 // FunctionNode is the interface for an operation that executes a function
@@ -10,28 +12,43 @@ type FunctionNode struct {
 	fun          func(args ...any) (any, error)
 	FunctionName string
 	Arguments    []Node
+	Signature    *types.Signature
+	Semantics    *Semantics
 }
 
-var _ Node = &FunctionNode{}
+var _ TypedNode = &FunctionNode{}
 
 // NewFunctionNode creates a new function node
 func NewFunctionNode(fun func(args ...any) (any, error), functionName string, arguments ...Node) *FunctionNode {
-	return &FunctionNode{fun, functionName, arguments}
+	return &FunctionNode{fun: fun, FunctionName: functionName, Arguments: arguments}
 }
 
 // Eval runs the appropriate logic to evaluate the node and produce a single result
 func (n *FunctionNode) Eval(data map[string]any) (any, error) {
+	return n.EvalIn(NewScope(data))
+}
+
+// EvalIn evaluates the node in the scope
+func (n *FunctionNode) EvalIn(scope *Scope) (any, error) {
 	// Evaluate all arguments
 	argVals := make([]any, len(n.Arguments))
 	for i, argument := range n.Arguments {
 		var err error
-		argVals[i], err = argument.Eval(data)
+		argVals[i], err = EvalIn(argument, scope)
 		if err != nil {
 			return nil, fmt.Errorf("%w, error in argument %d: %w", ErrNodeEvalFailed, i, err)
 		}
 	}
 
-	ret, err := n.fun(argVals...)
+	if n.Signature != nil {
+		var err error
+		argVals, err = n.Signature.Convert(n.FunctionName, argVals, n.Semantics.coercion())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNodeEvalFailed, err)
+		}
+	}
+
+	ret, err := n.call(argVals)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNodeEvalFailed, err)
 	}
@@ -39,6 +56,35 @@ func (n *FunctionNode) Eval(data map[string]any) (any, error) {
 	return ret, nil
 }
 
+func (n *FunctionNode) call(args []any) (ret any, err error) {
+	defer Recover(n.FunctionName, &err)
+
+	return n.fun(args...)
+}
+
+// Type checks the arguments against the function signature, if there is one, and returns the type of the result
+func (n *FunctionNode) Type(scope types.Type) (types.Type, error) {
+	args := make([]types.Type, len(n.Arguments))
+	for i, argument := range n.Arguments {
+		var err error
+		if l, ok := argument.(*LambdaNode); ok && i > 0 && args[0].Is(types.KindList) {
+			// Lambdas passed alongside a list are called with its items
+			args[i], err = l.TypeFor(scope, args[0].ElemType())
+		} else {
+			args[i], err = TypeOf(argument, scope)
+		}
+		if err != nil {
+			return types.Any, err
+		}
+	}
+
+	if n.Signature == nil {
+		return types.Any, nil
+	}
+
+	return n.Signature.Check(n.FunctionName, args) //nolint:wrapcheck // Error is already wrapped
+}
+
 // String returns the string representation
 func (n *FunctionNode) String() string {
 	args := []string{}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 func TestFunctionNode(t *testing.T) {
@@ -60,3 +61,48 @@ func TestFunctionNode(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionNodeType(t *testing.T) {
+	sig := &types.Signature{Params: []types.Param{{Name: "x", Type: types.Number}}, Returns: types.String}
+	scope := types.ObjectOf(map[string]types.Type{"name": types.String})
+
+	testCases := []struct {
+		name   string
+		sig    *types.Signature
+		args   []Node
+		result types.Type
+		err    error
+	}{
+		{"valid", sig, []Node{NewNumberNode(1)}, types.String, nil},
+		{"unknown signature", nil, []Node{NewStringNode("a"), NewStringNode("b")}, types.Any, nil},
+		{"arity", sig, []Node{}, types.Any, errors.New("type check failed: invalid number of arguments: function foo expects 1 arguments, got 0")},
+		{"argument type", sig, []Node{NewVariableNode("name")}, types.Any, errors.New("type check failed: argument 0 to function foo must be number, got string")},
+		{"argument error", nil, []Node{NewVariableNode("nme")}, types.Any, errors.New("type check failed: unknown field nme in nme")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := NewFunctionNode(nil, "foo", tc.args...)
+			n.Signature = tc.sig
+
+			actual, err := n.Type(scope)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result.String(), actual.String())
+		})
+	}
+}
+
+func TestFunctionNodeConvert(t *testing.T) {
+	n := NewFunctionNode(func(args ...any) (any, error) {
+		return args[0].(float64) * 2, nil
+	}, "double", NewStringNode("2"))
+	n.Signature = &types.Signature{Params: []types.Param{{Name: "x", Type: types.Number}}, Returns: types.Number}
+
+	res, err := n.Eval(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(4), res)
+
+	n.Arguments = []Node{NewStringNode("a")}
+	res, err = n.Eval(nil)
+	assert.ErrorEqual(t, errors.New("node evaluation failed: invalid argument 0 to function double: error parsing value as float, could not parse string 'a'"), err)
+	assert.Nil(t, res)
+}
@@ -0,0 +1,60 @@
+package nodes
+
+import (
+	"fmt"
+
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+// Lambda is the value produced by evaluating a lambda node. Calling it evaluates the body with the parameter bound
+// to the argument
+type Lambda func(arg any) (any, error)
+
+// LambdaNode is a node used to store a function of a single parameter, such as x => x.name == "bar"
+type LambdaNode struct {
+	Param string
+	Body  Node
+}
+
+var (
+	_ TypedNode  = &LambdaNode{}
+	_ ScopedNode = &LambdaNode{}
+)
+
+// NewLambdaNode creates a new lambda node
+func NewLambdaNode(param string, body Node) *LambdaNode {
+	return &LambdaNode{param, body}
+}
+
+// Eval captures the data so that the body can refer to it as well as to the parameter
+func (n *LambdaNode) Eval(data map[string]any) (any, error) {
+	return n.EvalIn(NewScope(data))
+}
+
+// EvalIn captures the scope so that the body can refer to its variables as well as to the parameter, which shadows
+// any variable of the same name. Each call gets its own scope layered over the captured one
+func (n *LambdaNode) EvalIn(scope *Scope) (any, error) {
+	return Lambda(func(arg any) (any, error) {
+		return EvalIn(n.Body, scope.With(n.Param, arg))
+	}), nil
+}
+
+// Type checks the body with the parameter given any type
+func (n *LambdaNode) Type(scope types.Type) (types.Type, error) {
+	return n.TypeFor(scope, types.Any)
+}
+
+// TypeFor checks the body with the parameter given the type of the values the lambda will be called with
+func (n *LambdaNode) TypeFor(scope, param types.Type) (types.Type, error) {
+	_, err := TypeOf(n.Body, scope.With(n.Param, param))
+	if err != nil {
+		return types.Any, err
+	}
+
+	return types.Lambda, nil
+}
+
+// String returns the string representation
+func (n *LambdaNode) String() string {
+	return fmt.Sprintf("%s => %s", n.Param, n.Body)
+}
@@ -0,0 +1,62 @@
+package nodes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+func TestLambdaNode(t *testing.T) {
+	n := NewLambdaNode("x", NewBinaryNode(NewVariableNode("x.a"), NewVariableNode("y"), "+"))
+	assert.Equal(t, "x => x.a+y", n.String())
+
+	data := map[string]any{"x": "shadowed", "y": 2}
+	res, err := n.Eval(data)
+	assert.Nil(t, err)
+
+	f, ok := res.(Lambda)
+	assert.Equal(t, true, ok)
+
+	actual, err := f(map[string]any{"a": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), actual)
+
+	actual, err = f(map[string]any{"a": 5})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(7), actual)
+
+	// The data the lambda was created with is left alone
+	assert.Equal(t, "shadowed", data["x"])
+	assert.Equal(t, 2, len(data))
+}
+
+func TestNestedLambdaNode(t *testing.T) {
+	// x => (y => x.Name + y + z)
+	inner := NewLambdaNode("y", NewBinaryNode(NewBinaryNode(NewVariableNode("x.Name"), NewVariableNode("y"), "+"), NewVariableNode("z"), "+"))
+	outer := NewLambdaNode("x", inner)
+
+	res, err := outer.Eval(map[string]any{"y": "shadowed", "z": "!"})
+	assert.Nil(t, err)
+
+	f, err := res.(Lambda)(struct{ Name string }{"a"})
+	assert.Nil(t, err)
+
+	actual, err := f.(Lambda)("b")
+	assert.Nil(t, err)
+	assert.Equal(t, "ab!", actual)
+}
+
+func TestLambdaNodeType(t *testing.T) {
+	scope := types.ObjectOf(map[string]types.Type{"y": types.Number})
+	n := NewLambdaNode("x", NewBinaryNode(NewVariableNode("x.a"), NewVariableNode("y"), "+"))
+
+	actual, err := n.Type(scope)
+	assert.Nil(t, err)
+	assert.Equal(t, "lambda", actual.String())
+
+	actual, err = n.TypeFor(scope, types.ObjectOf(map[string]types.Type{"b": types.Number}))
+	assert.ErrorEqual(t, errors.New("type check failed: unknown field a in x.a"), err)
+	assert.Equal(t, "any", actual.String())
+}
@@ -1,7 +1,10 @@
 // Package nodes provides implementation and interfaces for the various supported node types
 package nodes
 
-import "github.com/scottkgregory/parsley/internal/helpers"
+import (
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/types"
+)
 
 // ErrNodeEvalFailed is returned when a node failed to evaluate correctly
 const ErrNodeEvalFailed = helpers.ConstError("node evaluation failed")
@@ -16,3 +19,19 @@ type Node interface {
 	Eval(data map[string]any) (any, error)
 	String() string
 }
+
+// TypedNode is implemented by nodes which can work out the type of their result without being evaluated
+type TypedNode interface {
+	Node
+	Type(scope types.Type) (types.Type, error)
+}
+
+// TypeOf returns the type the node will produce when evaluated against data of the given shape. Nodes which
+// don't implement TypedNode are assumed to produce any type
+func TypeOf(n Node, scope types.Type) (types.Type, error) {
+	if t, ok := n.(TypedNode); ok {
+		return t.Type(scope)
+	}
+
+	return types.Any, nil
+}
@@ -3,7 +3,9 @@ package nodes
 import (
 	"fmt"
 
+	"github.com/scottkgregory/parsley/internal/decimal"
 	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 // NumberNode is a node used to store a number
@@ -11,7 +13,10 @@ type NumberNode struct {
 	Number any
 }
 
-var _ Node = &NumberNode{}
+var (
+	_ TypedNode  = &NumberNode{}
+	_ ScopedNode = &NumberNode{}
+)
 
 // NewNumberNode creates a new number node
 func NewNumberNode(number any) *NumberNode {
@@ -20,6 +25,14 @@ func NewNumberNode(number any) *NumberNode {
 
 // Eval runs the appropriate logic to evaluate the node and produce a single result
 func (n *NumberNode) Eval(_ map[string]any) (any, error) {
+	// Decimals and integers are kept exact
+	switch x := n.Number.(type) {
+	case decimal.Decimal:
+		return x, nil
+	case int64:
+		return x, nil
+	}
+
 	ret, err := helpers.ToFloat64(n.Number)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNodeEvalFailed, err)
@@ -28,6 +41,16 @@ func (n *NumberNode) Eval(_ map[string]any) (any, error) {
 	return ret, nil
 }
 
+// EvalIn returns the value, which doesn't depend on the scope
+func (n *NumberNode) EvalIn(_ *Scope) (any, error) {
+	return n.Eval(nil)
+}
+
+// Type returns the type of the result
+func (n *NumberNode) Type(_ types.Type) (types.Type, error) {
+	return types.Number, nil
+}
+
 // String returns the string representation
 func (n *NumberNode) String() string {
 	return fmt.Sprintf("%v", n.Number)
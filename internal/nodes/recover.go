@@ -0,0 +1,85 @@
+package nodes
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+// ErrFunctionPanicked is returned when a user supplied function or node panics
+const ErrFunctionPanicked = helpers.ConstError("function panicked")
+
+// PanicError holds the details of a recovered panic
+type PanicError struct {
+	Name  string
+	Value any
+	Stack []byte
+}
+
+var _ error = &PanicError{}
+
+// Error returns the error as a string
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", ErrFunctionPanicked, e.Name, e.Value)
+}
+
+// Unwrap allows the error to be matched against ErrFunctionPanicked
+func (e *PanicError) Unwrap() error {
+	return ErrFunctionPanicked
+}
+
+// Recover converts a panic in to a PanicError and stores it in err. It must be deferred directly so that the
+// panic can be recovered
+func Recover(name string, err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{name, r, debug.Stack()}
+	}
+}
+
+// RecoverNode wraps a user supplied node, returning any panic as an error rather than crashing the caller
+type RecoverNode struct {
+	Node Node
+	Name string
+}
+
+var (
+	_ TypedNode  = &RecoverNode{}
+	_ ScopedNode = &RecoverNode{}
+)
+
+// NewRecoverNode creates a new recover node
+func NewRecoverNode(name string, node Node) *RecoverNode {
+	return &RecoverNode{node, name}
+}
+
+// Eval evaluates the wrapped node
+func (n *RecoverNode) Eval(data map[string]any) (any, error) {
+	return n.EvalIn(NewScope(data))
+}
+
+// EvalIn evaluates the wrapped node in the scope
+func (n *RecoverNode) EvalIn(scope *Scope) (ret any, err error) {
+	defer Recover(n.Name, &err)
+
+	return EvalIn(n.Node, scope)
+}
+
+// Type returns the type of the wrapped node
+func (n *RecoverNode) Type(scope types.Type) (ret types.Type, err error) {
+	defer Recover(n.Name, &err)
+
+	return TypeOf(n.Node, scope)
+}
+
+// String returns the string representation of the wrapped node
+func (n *RecoverNode) String() (ret string) {
+	defer func() {
+		if r := recover(); r != nil {
+			ret = n.Name
+		}
+	}()
+
+	return n.Node.String()
+}
@@ -0,0 +1,61 @@
+package nodes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+type panicNode struct{}
+
+func (p *panicNode) Eval(_ map[string]any) (any, error) { panic("eval") }
+
+func (p *panicNode) String() string { panic("string") }
+
+func TestRecoverNode(t *testing.T) {
+	n := NewRecoverNode("£", &panicNode{})
+
+	res, err := n.Eval(nil)
+	assert.Nil(t, res)
+	assert.ErrorEqual(t, errors.New("function panicked: £: eval"), err)
+	assert.ErrorIs(t, ErrFunctionPanicked, err)
+
+	var panicErr *PanicError
+	assert.Equal(t, true, errors.As(err, &panicErr))
+	assert.Equal(t, "£", panicErr.Name)
+	assert.Equal(t, "eval", panicErr.Value)
+	assert.Equal(t, true, strings.Contains(string(panicErr.Stack), "panicNode"))
+
+	assert.Equal(t, "£", n.String())
+
+	typ, err := n.Type(types.Any)
+	assert.Nil(t, err)
+	assert.Equal(t, "any", typ.String())
+}
+
+func TestRecoverNodePassthrough(t *testing.T) {
+	n := NewRecoverNode("£", NewBinaryNode(NewNumberNode(1), NewNumberNode(2), "+"))
+
+	res, err := n.Eval(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), res)
+	assert.Equal(t, "1+2", n.String())
+
+	typ, err := n.Type(types.Any)
+	assert.Nil(t, err)
+	assert.Equal(t, "number", typ.String())
+}
+
+func TestFunctionNodePanic(t *testing.T) {
+	n := NewFunctionNode(func(args ...any) (any, error) {
+		return args[0].(bool), nil
+	}, "foo")
+
+	res, err := n.Eval(nil)
+	assert.Nil(t, res)
+	assert.ErrorEqual(t, errors.New("node evaluation failed: function panicked: foo: runtime error: index out of range [0] with length 0"), err)
+	assert.ErrorIs(t, ErrFunctionPanicked, err)
+}
@@ -0,0 +1,74 @@
+package nodes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+// RegexpNode is a node used to store a regular expression which was compiled when the expression was parsed
+type RegexpNode struct {
+	Pattern *regexp.Regexp
+}
+
+var (
+	_ TypedNode  = &RegexpNode{}
+	_ ScopedNode = &RegexpNode{}
+)
+
+// NewRegexpNode compiles the pattern in to a new regexp node
+func NewRegexpNode(pattern string) (*RegexpNode, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+
+	return &RegexpNode{re}, nil
+}
+
+// Eval returns the compiled regular expression
+func (n *RegexpNode) Eval(_ map[string]any) (any, error) {
+	return n.Pattern, nil
+}
+
+// EvalIn returns the value, which doesn't depend on the scope
+func (n *RegexpNode) EvalIn(_ *Scope) (any, error) {
+	return n.Eval(nil)
+}
+
+// Type returns the type of the result
+func (n *RegexpNode) Type(_ types.Type) (types.Type, error) {
+	return types.Regexp, nil
+}
+
+// String returns the string representation
+func (n *RegexpNode) String() string {
+	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(n.Pattern.String(), `"`, `\"`))
+}
+
+// match checks whether the value matches the regular expression, compiling it first if it's still a string
+func match(op string, a, b any, coercion helpers.CoercionPolicy) (any, error) {
+	re, err := types.Convert(types.Regexp, b, coercion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error in right side: %w", ErrComparisonFailed, err)
+	}
+
+	// Missing values never match
+	s := ""
+	if a != nil {
+		s, err = coercion.ToString(a)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error in left side: %w", ErrComparisonFailed, err)
+		}
+	}
+
+	matched := re.(*regexp.Regexp).MatchString(s)
+	if op == "!~" {
+		return !matched, nil
+	}
+
+	return matched, nil
+}
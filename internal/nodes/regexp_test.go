@@ -0,0 +1,55 @@
+package nodes
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+func TestRegexpNode(t *testing.T) {
+	n, err := NewRegexpNode(`^"a"+$`)
+	assert.Nil(t, err)
+
+	res, err := n.Eval(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, true, res.(*regexp.Regexp).MatchString(`"a"`))
+	assert.Equal(t, `"^\"a\"+$"`, n.String())
+
+	typ, err := n.Type(types.Any)
+	assert.Nil(t, err)
+	assert.Equal(t, "regexp", typ.String())
+
+	_, err = NewRegexpNode(`(`)
+	assert.ErrorEqual(t, errors.New("invalid regular expression: error parsing regexp: missing closing ): `(`"), err)
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		op     string
+		a, b   any
+		result any
+		err    error
+	}{
+		{"=~", "abc", "^a", true, nil},
+		{"=~", "abc", regexp.MustCompile("^b"), false, nil},
+		{"!~", "abc", "^b", true, nil},
+		{"=~", nil, "^$", true, nil},
+		{"=~", 12, `^\d+$`, true, nil},
+		{"=~", "abc", 12, nil, errors.New("error running comparison: error in right side: expected regular expression, got int")},
+		{"=~", "abc", "(", nil, errors.New("error running comparison: error in right side: invalid regular expression: error parsing regexp: missing closing ): `(`")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v%s%v", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := Calculate(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrComparisonFailed, err)
+			}
+		})
+	}
+}
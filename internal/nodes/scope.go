@@ -0,0 +1,76 @@
+package nodes
+
+import "maps"
+
+// Scope holds the variables visible while a node is evaluated, which are the data along with the parameters of any
+// lambdas the node is inside
+type Scope struct {
+	data   map[string]any
+	parent *Scope
+	name   string
+	value  any
+}
+
+// ScopedNode is implemented by nodes which can be evaluated in a scope, so that lambda parameters are visible to them
+type ScopedNode interface {
+	Node
+	EvalIn(scope *Scope) (any, error)
+}
+
+// NewScope creates a scope holding the data
+func NewScope(data map[string]any) *Scope {
+	return &Scope{data: data}
+}
+
+// With returns a scope where the name refers to the value, shadowing any variable of the same name
+func (s *Scope) With(name string, value any) *Scope {
+	return &Scope{parent: s, name: name, value: value}
+}
+
+// Lookup returns the value of the variable, and whether it is set
+func (s *Scope) Lookup(name string) (any, bool) {
+	for ; s.parent != nil; s = s.parent {
+		if s.name == name {
+			return s.value, true
+		}
+	}
+
+	x, ok := s.data[name]
+
+	return x, ok
+}
+
+// Data returns the variables in the scope as a map, for nodes which don't implement ScopedNode. Outside of a lambda
+// this is the data itself, otherwise a copy of the data with the parameters added
+func (s *Scope) Data() map[string]any {
+	if s.parent == nil {
+		return s.data
+	}
+
+	var layers []*Scope
+	for ; s.parent != nil; s = s.parent {
+		layers = append(layers, s)
+	}
+
+	ret := maps.Clone(s.data)
+	if ret == nil {
+		ret = map[string]any{}
+	}
+
+	// Inner parameters are added last, so that they shadow outer ones
+	for i := len(layers) - 1; i >= 0; i-- {
+		ret[layers[i].name] = layers[i].value
+	}
+
+	return ret
+}
+
+// EvalIn evaluates the node in the scope. Nodes which don't implement ScopedNode are given the variables in the scope
+// as a map
+func EvalIn(n Node, scope *Scope) (any, error) {
+	if s, ok := n.(ScopedNode); ok {
+		return s.EvalIn(scope)
+	}
+
+	return n.Eval(scope.Data())
+}
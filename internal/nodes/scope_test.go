@@ -0,0 +1,54 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestScope(t *testing.T) {
+	data := map[string]any{"x": "data", "y": 2}
+	root := NewScope(data)
+	inner := root.With("x", 1).With("z", 3).With("x", nil)
+
+	testCases := []struct {
+		name   string
+		scope  *Scope
+		result any
+		ok     bool
+	}{
+		{"x", root, "data", true},
+		{"x", root.With("x", 1), 1, true},
+		{"x", inner, nil, true},
+		{"y", inner, 2, true},
+		{"z", inner, 3, true},
+		{"missing", inner, nil, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := tc.scope.Lookup(tc.name)
+			assert.Equal(t, tc.result, actual)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+
+	assert.Equal(t, map[string]any{"x": nil, "y": 2, "z": 3}, inner.Data())
+	assert.Equal(t, map[string]any{"x": "data", "y": 2}, data)
+	assert.Equal(t, map[string]any{"a": 1}, NewScope(nil).With("a", 1).Data())
+}
+
+func TestEvalIn(t *testing.T) {
+	data := map[string]any{"y": 2}
+
+	// Nodes which only understand maps see the data unchanged outside a lambda, and with the parameters inside one
+	m := NewMockNode(data, "outside", nil, "m")
+	actual, err := EvalIn(m, NewScope(data))
+	assert.Nil(t, err)
+	assert.Equal(t, "outside", actual)
+	assert.Equal(t, true, m.evalCalled)
+
+	m = NewMockNode(map[string]any{"x": 1, "y": 2}, "inside", nil, "m")
+	actual, err = EvalIn(NewRecoverNode("custom", m), NewScope(data).With("x", 1))
+	assert.Nil(t, err)
+	assert.Equal(t, "inside", actual)
+}
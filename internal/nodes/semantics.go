@@ -0,0 +1,268 @@
+package nodes
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+
+	"github.com/scottkgregory/parsley/internal/decimal"
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+// ErrIntegerOverflow is returned when the result of integer arithmetic doesn't fit in an int64
+const ErrIntegerOverflow = helpers.ConstError("integer overflow")
+
+// ErrDivisionByZero is returned when an integer or decimal is divided by zero, or the remainder of a division by zero
+// is taken
+const ErrDivisionByZero = helpers.ErrDivisionByZero
+
+// Semantics controls how values are combined when expressions are evaluated. Nodes built by a parser share a
+// pointer to its semantics, nil uses the defaults
+type Semantics struct {
+	// Decimal switches arithmetic on numbers to exact decimals when set, rounding division using the context
+	Decimal *decimal.Context
+
+	// Integers keeps arithmetic on two integers as an int64, rather than converting both to float64
+	Integers bool
+
+	// Coercion decides which implicit conversions are made between types
+	Coercion helpers.CoercionPolicy
+
+	// Nulls treats nil as an unknown value, as SQL does. Operations involving nil produce nil, other than && and ||
+	// which follow Kleene's three-valued logic
+	Nulls bool
+}
+
+// IsNull checks whether the value is nil, or a nil pointer
+func IsNull(v any) bool {
+	return !indirect(reflect.ValueOf(v)).IsValid()
+}
+
+// nulls checks whether nil values are unknown, and either value is nil
+func (s *Semantics) nulls(a, b any) bool {
+	return s != nil && s.Nulls && (IsNull(a) || IsNull(b))
+}
+
+// logical performs && or ||. When nil is unknown a result is still produced if the known side decides it, such as
+// false && nil, otherwise the result is unknown
+func (s *Semantics) logical(op string, a, b any) (any, error) {
+	// The value which decides the result on its own, true for || and false for &&
+	decisive := op == "||"
+
+	known := []bool{}
+	for _, v := range []any{a, b} {
+		if s.nulls(v, v) {
+			continue
+		}
+
+		x, err := s.coercion().ToBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrComparisonFailed, err)
+		}
+
+		known = append(known, x)
+	}
+
+	switch {
+	case slices.Contains(known, decisive):
+		return decisive, nil
+	case len(known) < 2:
+		return nil, nil
+	}
+
+	return !decisive, nil
+}
+
+// coercion returns the coercion policy, nil semantics are lenient
+func (s *Semantics) coercion() helpers.CoercionPolicy {
+	if s == nil {
+		return helpers.CoercionLenient
+	}
+
+	return s.Coercion
+}
+
+// DecimalContext returns the context for decimal arithmetic, and whether it is enabled
+func (s *Semantics) DecimalContext() (decimal.Context, bool) {
+	if s == nil || s.Decimal == nil {
+		return decimal.DefaultContext, false
+	}
+
+	return *s.Decimal, true
+}
+
+// integers checks whether integer arithmetic is enabled and both values are integers
+func (s *Semantics) integers(a, b any) bool {
+	return s != nil && s.Integers && isInteger(a) && isInteger(b)
+}
+
+func isInteger(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.CanInt() || rv.CanUint()
+}
+
+// calculateInteger performs the operation on two integers. Results stay as an int64 unless a fraction is needed,
+// and an error is returned rather than silently overflowing
+func calculateInteger(op string, a, b any) (any, error) {
+	switch op {
+	case "<", ">", "==":
+		// Compared exactly, even when an unsigned value doesn't fit in an int64
+		c := compareNumbers(reflect.ValueOf(a), reflect.ValueOf(b))
+		return (op == "<" && c < 0) || (op == ">" && c > 0) || (op == "==" && c == 0), nil
+	}
+
+	x, err := helpers.ToInt64(a)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w: %w", ErrComparisonFailed, ErrIntegerOverflow, err)
+	}
+
+	y, err := helpers.ToInt64(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w: %w", ErrComparisonFailed, ErrIntegerOverflow, err)
+	}
+
+	ret, err := integerOp(op, x, y)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrComparisonFailed, err)
+	}
+
+	return ret, nil
+}
+
+func integerOp(op string, x, y int64) (any, error) {
+	switch op {
+	case "+":
+		if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+			return nil, fmt.Errorf("%w: %d + %d", ErrIntegerOverflow, x, y)
+		}
+
+		return x + y, nil
+	case "-":
+		if (y < 0 && x > math.MaxInt64+y) || (y > 0 && x < math.MinInt64+y) {
+			return nil, fmt.Errorf("%w: %d - %d", ErrIntegerOverflow, x, y)
+		}
+
+		return x - y, nil
+	case "*":
+		ret, ok := multiply(x, y)
+		if !ok {
+			return nil, fmt.Errorf("%w: %d * %d", ErrIntegerOverflow, x, y)
+		}
+
+		return ret, nil
+	case "/":
+		if y == 0 {
+			return nil, fmt.Errorf("%w: %d / %d", ErrDivisionByZero, x, y)
+		}
+
+		// Only exact divisions stay as integers
+		if x%y == 0 {
+			if x == math.MinInt64 && y == -1 {
+				return nil, fmt.Errorf("%w: %d / %d", ErrIntegerOverflow, x, y)
+			}
+
+			return x / y, nil
+		}
+
+		return float64(x) / float64(y), nil
+	case "%":
+		if y == 0 {
+			return nil, fmt.Errorf("%w: %d %% %d", ErrDivisionByZero, x, y)
+		}
+
+		return x % y, nil
+	case "^":
+		// Negative powers need a fraction
+		if y < 0 {
+			return math.Pow(float64(x), float64(y)), nil
+		}
+
+		// Exponentiation by squaring
+		ret, base, ok := int64(1), x, true
+		for n := y; n > 0 && ok; n >>= 1 {
+			if n&1 == 1 {
+				ret, ok = multiply(ret, base)
+			}
+
+			if n > 1 && ok {
+				base, ok = multiply(base, base)
+			}
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("%w: %d ^ %d", ErrIntegerOverflow, x, y)
+		}
+
+		return ret, nil
+	}
+
+	return nil, fmt.Errorf("unrecognised op: %s", op)
+}
+
+// multiply returns x * y, and false if it overflows
+func multiply(x, y int64) (int64, bool) {
+	if x == 0 || y == 0 {
+		return 0, true
+	}
+
+	ret := x * y
+	if ret/y != x || (x == -1 && y == math.MinInt64) || (y == -1 && x == math.MinInt64) {
+		return 0, false
+	}
+
+	return ret, true
+}
+
+func isDecimal(v any) bool {
+	_, ok := v.(decimal.Decimal)
+	return ok
+}
+
+// calculateDecimal performs the operation on numbers as exact decimals
+func calculateDecimal(op string, a, b any, ctx decimal.Context) (any, error) {
+	x, err := decimal.From(a)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error in left side: %w", ErrComparisonFailed, err)
+	}
+
+	y, err := decimal.From(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error in right side: %w", ErrComparisonFailed, err)
+	}
+
+	var ret decimal.Decimal
+	switch op {
+	case "<":
+		return x.Cmp(y) < 0, nil
+	case ">":
+		return x.Cmp(y) > 0, nil
+	case "==":
+		return x.Cmp(y) == 0, nil
+	case "+":
+		return x.Add(y), nil
+	case "-":
+		return x.Sub(y), nil
+	case "*":
+		return x.Mul(y), nil
+	case "/":
+		ret, err = x.Div(y, ctx)
+	case "%":
+		ret, err = x.Mod(y)
+	case "^":
+		// Whole powers are exact, anything else can only be approximated
+		if n, ok := y.Int64(); ok && y.IsInteger() {
+			ret, err = x.Pow(n, ctx)
+		} else {
+			ret, err = decimal.FromFloat(math.Pow(x.Float64(), y.Float64()))
+		}
+	default:
+		return nil, fmt.Errorf("%w: unrecognised op: %s", ErrComparisonFailed, op)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrComparisonFailed, err)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,243 @@
+package nodes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/decimal"
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+func TestCalculateDecimal(t *testing.T) {
+	ctx := decimal.Context{Scale: 2, Rounding: decimal.HalfUp}
+	s := &Semantics{Decimal: &ctx}
+	d := func(s string) decimal.Decimal {
+		ret, err := decimal.Parse(s)
+		assert.Nil(t, err)
+		return ret
+	}
+
+	testCases := []struct {
+		s      *Semantics
+		op     string
+		a, b   any
+		result any
+		err    error
+	}{
+		{s, "+", 0.1, 0.2, "0.3", nil},
+		{s, "==", 0.1, d("0.10"), true, nil},
+		{s, "<", 1, d("1.01"), true, nil},
+		{s, ">", "1.01", 1, nil, errors.New("error running comparison: only one side of comparison was a string: string int")},
+		{s, "-", d("10.00"), 0.01, "9.99", nil},
+		{s, "*", d("19.99"), 3, "59.97", nil},
+		{s, "/", 10, 3, "3.33", nil},
+		{s, "/", 20, 3, "6.67", nil},
+		{s, "/", 1, 0, nil, errors.New("error running comparison: division by zero")},
+		{s, "^", d("1.1"), 2, "1.21", nil},
+		{s, "^", 4, 0.5, "2", nil},
+		{s, "+", nil, 1, nil, errors.New("error running comparison: error in left side: error parsing value as decimal, invalid type: <nil>")},
+		{nil, "+", d("0.1"), 0.2, "0.3", nil},
+		{nil, "/", d("1"), 3, "0.3333333333333333", nil},
+		{nil, "+", 0.1, 0.2, 0.30000000000000004, nil},
+		{nil, "==", []any{d("1.0")}, []any{1}, true, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v%s%v", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := tc.s.Calculate(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			if x, ok := actual.(decimal.Decimal); ok {
+				actual = x.String()
+			}
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
+
+func TestDecimalNodes(t *testing.T) {
+	ctx := decimal.DefaultContext
+	s := &Semantics{Decimal: &ctx}
+
+	n := NewUnaryNode(NewVariableNode("price"), "-")
+	n.Semantics = s
+
+	actual, err := n.Eval(map[string]any{"price": 0.1})
+	assert.Nil(t, err)
+	assert.Equal(t, "-0.1", fmt.Sprint(actual))
+
+	d, err := decimal.Parse("1.50")
+	assert.Nil(t, err)
+
+	actual, err = NewNumberNode(d).Eval(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, d, actual)
+}
+
+func TestCalculateInteger(t *testing.T) {
+	s := &Semantics{Integers: true}
+
+	testCases := []struct {
+		op     string
+		a, b   any
+		result any
+		err    error
+	}{
+		{"==", int64(9007199254740993), int64(9007199254740992), false, nil},
+		{"<", int64(9007199254740992), uint64(9007199254740993), true, nil},
+		{">", uint64(math.MaxUint64), int64(math.MaxInt64), true, nil},
+		{"+", int64(9007199254740992), 1, int64(9007199254740993), nil},
+		{"-", int8(3), uint(5), int64(-2), nil},
+		{"*", int64(3), int64(-4), int64(-12), nil},
+		{"%", int64(7), int64(3), int64(1), nil},
+		{"%", int64(-7), int64(3), int64(-1), nil},
+		{"/", int64(12), int64(4), int64(3), nil},
+		{"/", int64(7), int64(2), 3.5, nil},
+		{"^", int64(2), int64(62), int64(4611686018427387904), nil},
+		{"^", int64(2), int64(-1), 0.5, nil},
+		{"+", int64(1), 0.5, 1.5, nil},
+		{"+", int64(math.MaxInt64), int64(1), nil, errors.New("error running comparison: integer overflow: 9223372036854775807 + 1")},
+		{"-", int64(math.MinInt64), int64(1), nil, errors.New("error running comparison: integer overflow: -9223372036854775808 - 1")},
+		{"*", int64(math.MaxInt64), int64(2), nil, errors.New("error running comparison: integer overflow: 9223372036854775807 * 2")},
+		{"^", int64(2), int64(63), nil, errors.New("error running comparison: integer overflow: 2 ^ 63")},
+		{"/", int64(math.MinInt64), int64(-1), nil, errors.New("error running comparison: integer overflow: -9223372036854775808 / -1")},
+		{"%", int64(1), int64(0), nil, errors.New("error running comparison: division by zero: 1 % 0")},
+		{"/", int64(1), int64(0), nil, errors.New("error running comparison: division by zero: 1 / 0")},
+		{"+", uint64(math.MaxUint64), int64(1), nil, errors.New("error running comparison: integer overflow: error parsing value as int, 18446744073709551615 is out of range")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v%s%v", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := s.Calculate(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+
+	actual, err := Calculate("+", int64(9007199254740992), int64(1))
+	assert.Nil(t, err)
+	assert.Equal(t, float64(9007199254740992), actual)
+}
+
+func TestIntegerNodes(t *testing.T) {
+	n := NewUnaryNode(NewVariableNode("id"), "-")
+	n.Semantics = &Semantics{Integers: true}
+
+	actual, err := n.Eval(map[string]any{"id": int64(9007199254740993)})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-9007199254740993), actual)
+
+	_, err = n.Eval(map[string]any{"id": int64(math.MinInt64)})
+	assert.ErrorIs(t, ErrIntegerOverflow, err)
+
+	actual, err = NewNumberNode(int64(12)).Eval(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), actual)
+}
+
+func TestCalculateCoercion(t *testing.T) {
+	strict := &Semantics{Coercion: helpers.CoercionStrict}
+	js := &Semantics{Coercion: helpers.CoercionJS}
+
+	testCases := []struct {
+		s      *Semantics
+		op     string
+		a, b   any
+		result any
+		err    error
+	}{
+		{nil, "==", "", nil, true, nil},
+		{nil, "&&", "yes", 1, true, nil},
+		{strict, "==", "", nil, nil, errors.New("error running comparison: only one side of comparison was a string: string <nil>")},
+		{strict, "&&", "yes", true, nil, errors.New("error running comparison: error parsing value as bool, invalid type: string")},
+		{strict, "&&", true, false, false, nil},
+		{strict, "+", 1, 2, float64(3), nil},
+		{strict, "+", "a", "b", "ab", nil},
+		{strict, ">", time.Unix(1, 0), "1970-01-01T00:00:00Z", nil, errors.New("error running comparison: error parsing value as time, invalid type: string")},
+		{strict, "=~", 12, "1", nil, errors.New("error running comparison: error in left side: error converting value to string, invalid type: int")},
+		{js, "+", "a", 1, "a1", nil},
+		{js, "+", nil, "a", "nulla", nil},
+		{js, "-", "10", "4", float64(6), nil},
+		{js, "<", "9", 10, true, nil},
+		{js, "==", "1", 1, true, nil},
+		{js, "==", "", nil, false, nil},
+		{js, "==", nil, nil, true, nil},
+		{js, "+", true, 1, float64(2), nil},
+		{js, "||", "", 0, false, nil},
+		{js, "&&", "a", []any{}, true, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v%s%v", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := tc.s.Calculate(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
+
+func TestCoercionNodes(t *testing.T) {
+	n := NewUnaryNode(NewStringNode("12"), "-")
+	_, err := n.Eval(nil)
+	assert.Nil(t, err)
+
+	n.Semantics = &Semantics{Coercion: helpers.CoercionStrict}
+	_, err = n.Eval(nil)
+	assert.ErrorEqual(t, errors.New("node evaluation failed: error parsing value as float, invalid type: string"), err)
+
+	f := NewFunctionNode(func(args ...any) (any, error) { return args[0], nil }, "f", NewStringNode("1.5"))
+	f.Signature = &types.Signature{Params: []types.Param{{Name: "x", Type: types.Number}}}
+
+	actual, err := f.Eval(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1.5, actual)
+
+	f.Semantics = &Semantics{Coercion: helpers.CoercionStrict}
+	_, err = f.Eval(nil)
+	assert.ErrorIs(t, types.ErrInvalidArgument, err)
+}
+
+func TestCalculateNulls(t *testing.T) {
+	s := &Semantics{Nulls: true}
+	var nilMap map[string]any
+	var nilPointer *int
+
+	testCases := []struct {
+		op     string
+		a, b   any
+		result any
+		err    error
+	}{
+		{"==", nil, nil, nil, nil},
+		{"==", "", nil, nil, nil},
+		{">", 1, nil, nil, nil},
+		{"+", nilPointer, 1, nil, nil},
+		{"in", "a", nilMap, false, nil},
+		{"in", "a", nil, nil, nil},
+		{"=~", nil, "a", nil, nil},
+		{"&&", false, nil, false, nil},
+		{"&&", nil, false, false, nil},
+		{"&&", true, nil, nil, nil},
+		{"&&", nil, nil, nil, nil},
+		{"&&", true, true, true, nil},
+		{"||", nil, true, true, nil},
+		{"||", false, nil, nil, nil},
+		{"||", false, false, false, nil},
+		{"||", nil, "blep", nil, errors.New("error running comparison: error parsing value as bool, could not parse string 'blep'")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v%s%v", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := s.Calculate(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+
+	n := NewUnaryNode(NewVariableNode("missing"), "-")
+	n.Semantics = s
+
+	actual, err := n.Eval(map[string]any{})
+	assert.Nil(t, err)
+	assert.Equal(t, nil, actual)
+}
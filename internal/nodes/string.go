@@ -3,6 +3,8 @@ package nodes
 import (
 	"fmt"
 	"strings"
+
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 // StringNode is a node used to store a string
@@ -10,7 +12,10 @@ type StringNode struct {
 	StringValue string
 }
 
-var _ Node = &StringNode{}
+var (
+	_ TypedNode  = &StringNode{}
+	_ ScopedNode = &StringNode{}
+)
 
 // NewStringNode creates a new string node
 func NewStringNode(stringValue string) *StringNode {
@@ -23,6 +28,16 @@ func (n *StringNode) Eval(_ map[string]any) (any, error) {
 
 }
 
+// EvalIn returns the value, which doesn't depend on the scope
+func (n *StringNode) EvalIn(_ *Scope) (any, error) {
+	return n.Eval(nil)
+}
+
+// Type returns the type of the result
+func (n *StringNode) Type(_ types.Type) (types.Type, error) {
+	return types.String, nil
+}
+
 // String returns the string representation
 func (n *StringNode) String() string {
 	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(n.StringValue, `"`, `\"`))
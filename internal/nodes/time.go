@@ -0,0 +1,216 @@
+package nodes
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+// isTemporal checks whether either value is a time or duration, in which case calculateTime should be used
+func isTemporal(a, b any) bool {
+	switch a.(type) {
+	case time.Time, time.Duration:
+		return true
+	}
+
+	switch b.(type) {
+	case time.Time, time.Duration:
+		return true
+	}
+
+	return false
+}
+
+// calculateTime performs the operation where at least one side is a time or a duration. Strings on the other side
+// are parsed to match, so that RFC3339 timestamps in the data can be compared against times
+func calculateTime(op string, a, b any, coercion helpers.CoercionPolicy) (any, error) {
+	a, b, err := matchTemporal(a, b, coercion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrComparisonFailed, err)
+	}
+
+	switch x := a.(type) {
+	case time.Time:
+		switch y := b.(type) {
+		case time.Time:
+			return timeOp(op, x, y)
+		case time.Duration:
+			switch op {
+			case "+":
+				return x.Add(y), nil
+			case "-":
+				return x.Add(-y), nil
+			}
+		}
+	case time.Duration:
+		switch y := b.(type) {
+		case time.Time:
+			if op == "+" {
+				return y.Add(x), nil
+			}
+		case time.Duration:
+			return durationOp(op, x, y)
+		case float64:
+			if op == "*" || op == "/" {
+				return scaleDuration(op, x, y)
+			}
+		}
+	case float64:
+		if y, ok := b.(time.Duration); ok && op == "*" {
+			return scaleDuration(op, y, x)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unsupported op for %T and %T: %s", ErrComparisonFailed, a, b, op)
+}
+
+// matchTemporal converts the other side of the operation to a time, duration or number to match
+func matchTemporal(a, b any, coercion helpers.CoercionPolicy) (any, any, error) {
+	var err error
+	switch a.(type) {
+	case time.Time:
+		if _, ok := b.(time.Duration); !ok {
+			b, err = coercion.ToTime(b)
+		}
+	case time.Duration:
+		switch b.(type) {
+		case time.Time, time.Duration:
+		case string:
+			b, err = coercion.ToDuration(b)
+		default:
+			b, err = coercion.ToFloat64(b)
+		}
+	default:
+		switch b.(type) {
+		case time.Time:
+			a, err = coercion.ToTime(a)
+		case time.Duration:
+			if _, ok := a.(string); ok {
+				a, err = coercion.ToDuration(a)
+			} else {
+				a, err = coercion.ToFloat64(a)
+			}
+		}
+	}
+
+	return a, b, err //nolint:wrapcheck // Error is wrapped by the caller
+}
+
+func timeOp(op string, a, b time.Time) (any, error) {
+	switch op {
+	case "<":
+		return a.Before(b), nil
+	case ">":
+		return a.After(b), nil
+	case "==":
+		return a.Equal(b), nil
+	case "-":
+		return a.Sub(b), nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported op for times: %s", ErrComparisonFailed, op)
+}
+
+func durationOp(op string, a, b time.Duration) (any, error) {
+	switch op {
+	case "<":
+		return a < b, nil
+	case ">":
+		return a > b, nil
+	case "==":
+		return a == b, nil
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("%w: %w: %s / %s", ErrComparisonFailed, ErrDivisionByZero, a, b)
+		}
+
+		return float64(a) / float64(b), nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported op for durations: %s", ErrComparisonFailed, op)
+}
+
+// scaleDuration multiplies or divides the duration by a number, returning an error rather than overflowing when the
+// result doesn't fit in a duration
+func scaleDuration(op string, d time.Duration, n float64) (any, error) {
+	var f float64
+	switch op {
+	case "*":
+		f = float64(d) * n
+	case "/":
+		if n == 0 {
+			return nil, fmt.Errorf("%w: %w: %s / %v", ErrComparisonFailed, ErrDivisionByZero, d, n)
+		}
+		f = float64(d) / n
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, so the upper bound is exclusive
+	if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return nil, fmt.Errorf("%w: %w: %s %s %v", ErrComparisonFailed, ErrIntegerOverflow, d, op, n)
+	}
+
+	return time.Duration(f), nil
+}
+
+// calculateTimeType works out the type produced by an operation where at least one side is a time or a duration
+func calculateTimeType(op string, a, b types.Type) (types.Type, error) {
+	comparison := op == "<" || op == ">" || op == "=="
+
+	// Strings are parsed to match the other side
+	if a.Is(types.KindString) {
+		a = b
+	}
+	if b.Is(types.KindString) {
+		b = a
+	}
+
+	switch {
+	case a.Is(types.KindAny) || b.Is(types.KindAny):
+		if comparison {
+			return types.Bool, nil
+		}
+
+		return types.Any, nil
+	case a.Is(types.KindTime) && b.Is(types.KindTime):
+		switch {
+		case comparison:
+			return types.Bool, nil
+		case op == "-":
+			return types.Duration, nil
+		}
+	case a.Is(types.KindTime) && b.Is(types.KindDuration):
+		if op == "+" || op == "-" {
+			return types.Time, nil
+		}
+	case a.Is(types.KindDuration) && b.Is(types.KindTime):
+		if op == "+" {
+			return types.Time, nil
+		}
+	case a.Is(types.KindDuration) && b.Is(types.KindDuration):
+		switch {
+		case comparison:
+			return types.Bool, nil
+		case op == "+" || op == "-":
+			return types.Duration, nil
+		case op == "/":
+			return types.Number, nil
+		}
+	case a.Is(types.KindDuration) && b.Is(types.KindNumber):
+		if op == "*" || op == "/" {
+			return types.Duration, nil
+		}
+	case a.Is(types.KindNumber) && b.Is(types.KindDuration):
+		if op == "*" {
+			return types.Duration, nil
+		}
+	}
+
+	return types.Any, fmt.Errorf("%w: unsupported op for %s and %s: %s", types.ErrTypeCheckFailed, a, b, op)
+}
@@ -0,0 +1,84 @@
+package nodes
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+func TestCalculateTime(t *testing.T) {
+	early := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
+	testCases := []struct {
+		op     string
+		a, b   any
+		result any
+		err    error
+	}{
+		{">", late, early, true, nil},
+		{"<", late, early, false, nil},
+		{"==", early, "2024-03-01T12:00:00Z", true, nil},
+		{">", "2024-03-01T12:30:00Z", early, true, nil},
+		{"-", late, early, time.Hour, nil},
+		{"-", late, time.Hour, early, nil},
+		{"+", early, time.Hour, late, nil},
+		{"+", time.Hour, early, late, nil},
+		{">", time.Hour, time.Minute, true, nil},
+		{"<", time.Minute, "1h", true, nil},
+		{"+", time.Hour, time.Minute, 61 * time.Minute, nil},
+		{"/", time.Hour, time.Minute, float64(60), nil},
+		{"*", time.Minute, float64(2), 2 * time.Minute, nil},
+		{"*", 3, time.Minute, 3 * time.Minute, nil},
+		{"/", time.Minute, 2, 30 * time.Second, nil},
+		{"/", time.Minute, 0, nil, errors.New("error running comparison: division by zero: 1m0s / 0")},
+		{"/", time.Minute, time.Duration(0), nil, errors.New("error running comparison: division by zero: 1m0s / 0s")},
+		{"*", time.Hour, 1e10, nil, errors.New("error running comparison: integer overflow: 1h0m0s * 1e+10")},
+		{"*", -1e10, time.Hour, nil, errors.New("error running comparison: integer overflow: 1h0m0s * -1e+10")},
+		{"/", time.Hour, 1e-10, nil, errors.New("error running comparison: integer overflow: 1h0m0s / 1e-10")},
+		{"+", early, early, nil, errors.New("error running comparison: unsupported op for times: +")},
+		{"*", time.Minute, time.Minute, nil, errors.New("error running comparison: unsupported op for durations: *")},
+		{">", early, "yesterday", nil, errors.New("error running comparison: error parsing value as time, could not parse string 'yesterday'")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v%s%v", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := Calculate(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrComparisonFailed, err)
+			}
+		})
+	}
+}
+
+func TestCalculateTimeType(t *testing.T) {
+	testCases := []struct {
+		op     string
+		a, b   types.Type
+		result string
+		err    error
+	}{
+		{">", types.Time, types.Time, "bool", nil},
+		{">", types.Time, types.String, "bool", nil},
+		{"-", types.Time, types.Time, "duration", nil},
+		{"-", types.Time, types.Duration, "time", nil},
+		{"+", types.Duration, types.Time, "time", nil},
+		{"/", types.Duration, types.Duration, "number", nil},
+		{"*", types.Number, types.Duration, "duration", nil},
+		{"+", types.Time, types.Any, "any", nil},
+		{"+", types.Time, types.Time, "any", errors.New("type check failed: unsupported op for time and time: +")},
+		{"<", types.Time, types.Bool, "any", errors.New("type check failed: unsupported op for time and bool: <")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s%s%s", tc.a, tc.op, tc.b), func(t *testing.T) {
+			actual, err := CalculateType(tc.op, tc.a, tc.b)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual.String())
+		})
+	}
+}
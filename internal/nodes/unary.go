@@ -3,32 +3,66 @@ package nodes
 import (
 	"fmt"
 
-	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/decimal"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 // UnaryNode is a node that has just a right side
 type UnaryNode struct {
-	Right Node
-	op    string
+	Right     Node
+	Semantics *Semantics
+	op        string
 }
 
+var (
+	_ TypedNode  = &UnaryNode{}
+	_ ScopedNode = &UnaryNode{}
+)
+
 // NewUnaryNode creates a nwe unary node
 func NewUnaryNode(right Node, op string) *UnaryNode {
-	return &UnaryNode{right, op}
+	return &UnaryNode{Right: right, op: op}
 }
 
 // Eval runs the appropriate logic to evaluate the node and produce a single result
 func (n *UnaryNode) Eval(data map[string]any) (any, error) {
+	return n.EvalIn(NewScope(data))
+}
+
+// EvalIn evaluates the node in the scope
+func (n *UnaryNode) EvalIn(scope *Scope) (any, error) {
 	if n.op != "-" {
 		return nil, fmt.Errorf("%w: unrecognised op: %s", ErrNodeEvalFailed, string(n.op))
 	}
 
-	val, err := n.Right.Eval(data)
+	val, err := EvalIn(n.Right, scope)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNodeEvalFailed, err)
 	}
 
-	aa, err := helpers.ToFloat64(val)
+	if n.Semantics.nulls(val, val) {
+		return nil, nil
+	}
+
+	if _, ok := n.Semantics.DecimalContext(); ok || isDecimal(val) {
+		d, err := decimal.From(val)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNodeEvalFailed, err)
+		}
+
+		return d.Neg(), nil
+	}
+
+	if n.Semantics.integers(val, val) {
+		ret, err := calculateInteger("-", int64(0), val)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNodeEvalFailed, err)
+		}
+
+		return ret, nil
+	}
+
+	aa, err := n.Semantics.coercion().ToFloat64(val)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNodeEvalFailed, err)
 	}
@@ -36,6 +70,24 @@ func (n *UnaryNode) Eval(data map[string]any) (any, error) {
 	return -aa, nil
 }
 
+// Type returns the type of the result
+func (n *UnaryNode) Type(scope types.Type) (types.Type, error) {
+	if n.op != "-" {
+		return types.Any, fmt.Errorf("%w: unrecognised op: %s", types.ErrTypeCheckFailed, n.op)
+	}
+
+	right, err := TypeOf(n.Right, scope)
+	if err != nil {
+		return types.Any, err
+	}
+
+	if !right.Is(types.KindAny, types.KindNumber) {
+		return types.Any, fmt.Errorf("%w: cannot negate %s", types.ErrTypeCheckFailed, right)
+	}
+
+	return types.Number, nil
+}
+
 // String returns the string representation
 func (n *UnaryNode) String() string {
 	return fmt.Sprintf("%s(%s)", n.op, n.Right.String())
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 func TestUnaryNode(t *testing.T) {
@@ -49,3 +50,25 @@ func TestUnaryNode(t *testing.T) {
 		})
 	}
 }
+
+func TestUnaryNodeType(t *testing.T) {
+	testCases := []struct {
+		right  Node
+		op     string
+		result types.Type
+		err    error
+	}{
+		{NewNumberNode(1), "-", types.Number, nil},
+		{NewMockNode(nil, 1, nil, "1"), "-", types.Number, nil},
+		{NewStringNode("a"), "-", types.Any, errors.New("type check failed: cannot negate string")},
+		{NewVariableNode("a.b"), "-", types.Any, errors.New("type check failed: cannot access field b of number in a.b")},
+		{NewNumberNode(1), "?", types.Any, errors.New("type check failed: unrecognised op: ?")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.right.String(), func(t *testing.T) {
+			actual, err := NewUnaryNode(tc.right, tc.op).Type(types.ObjectOf(map[string]types.Type{"a": types.Number}))
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result.String(), actual.String())
+		})
+	}
+}
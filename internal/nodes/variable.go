@@ -1,24 +1,52 @@
 package nodes
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
+
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 // VariableNode is a node used to store a variable reference
 type VariableNode struct {
 	VariableName string
+
+	// Function is set when a function has the same name, so that a missing variable is reported as a function
+	// called without brackets, such as pi rather than pi(), instead of evaluating to nil
+	Function bool
 }
 
-var _ Node = &VariableNode{}
+var (
+	_ TypedNode  = &VariableNode{}
+	_ ScopedNode = &VariableNode{}
+)
 
 // NewVariableNode creates a new variable node
 func NewVariableNode(variableName string) *VariableNode {
-	return &VariableNode{variableName}
+	return &VariableNode{VariableName: variableName}
 }
 
 // Eval runs the appropriate logic to evaluate the node and produce a single result
 func (n *VariableNode) Eval(data map[string]any) (any, error) {
-	return getValue(strings.Split(n.VariableName, "."), data), nil
+	return n.EvalIn(NewScope(data))
+}
+
+// EvalIn looks the variable up in the scope
+func (n *VariableNode) EvalIn(scope *Scope) (any, error) {
+	key := strings.Split(n.VariableName, ".")
+	if n.Function {
+		if _, ok := scope.Lookup(key[0]); !ok {
+			return nil, fmt.Errorf("%w: %s is a function, call it as %s()", ErrNodeEvalFailed, n.VariableName, n.VariableName)
+		}
+	}
+
+	return getValue(key, scope), nil
+}
+
+// Type returns the type of the variable, as described by the scope
+func (n *VariableNode) Type(scope types.Type) (types.Type, error) {
+	return scope.Path(strings.Split(n.VariableName, ".")) //nolint:wrapcheck // Error is already wrapped
 }
 
 // String returns the string representation
@@ -26,15 +54,35 @@ func (n *VariableNode) String() string {
 	return n.VariableName
 }
 
-func getValue(key []string, data map[string]any) any {
+// getValue follows the path of keys through the data, looking in the fields of maps and structs
+func getValue(key []string, scope *Scope) any {
 	if len(key) == 0 {
 		return nil
 	}
 
-	x := data[key[0]]
-	if x, ok := x.(map[string]any); ok {
-		return getValue(key[1:], x)
+	x, _ := scope.Lookup(key[0])
+	for _, name := range key[1:] {
+		if m, ok := x.(map[string]any); ok {
+			x = m[name]
+			continue
+		}
+
+		if !hasFields(x) {
+			return x
+		}
+
+		x, _ = Field(x, name)
 	}
 
 	return x
 }
+
+// hasFields checks whether the value is a map or a struct, other than a time or decimal
+func hasFields(v any) bool {
+	switch indirect(reflect.ValueOf(v)).Kind() { //nolint:exhaustive // Only maps and structs have fields
+	case reflect.Map, reflect.Struct:
+		return isStructured(v)
+	}
+
+	return false
+}
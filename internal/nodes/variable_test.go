@@ -1,9 +1,11 @@
 package nodes
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 func TestVariableEval(t *testing.T) {
@@ -16,6 +18,12 @@ func TestVariableEval(t *testing.T) {
 		{"foo", map[string]any{"foo": "bar"}, "bar", "foo"},
 		{"foo", map[string]any{"foo": 2}, 2, "foo"},
 		{"foo.bar", map[string]any{"foo": map[string]any{"bar": "baz"}}, "baz", "foo.bar"},
+		{"foo", map[string]any{"foo": map[string]any{"bar": "baz"}}, map[string]any{"bar": "baz"}, "foo"},
+		{"foo.Bar", map[string]any{"foo": struct{ Bar string }{"baz"}}, "baz", "foo.Bar"},
+		{"foo.bar.baz", map[string]any{"foo": &struct {
+			Bar map[string]int `json:"bar"`
+		}{map[string]int{"baz": 1}}}, 1, "foo.bar.baz"},
+		{"foo.missing", map[string]any{"foo": struct{ Bar string }{"baz"}}, nil, "foo.missing"},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.a, func(t *testing.T) {
@@ -29,3 +37,29 @@ func TestVariableEval(t *testing.T) {
 		})
 	}
 }
+
+func TestVariableType(t *testing.T) {
+	scope := types.ObjectOf(map[string]types.Type{
+		"foo": types.ObjectOf(map[string]types.Type{"bar": types.String}),
+	})
+
+	actual, err := NewVariableNode("foo.bar").Type(scope)
+	assert.Nil(t, err)
+	assert.Equal(t, "string", actual.String())
+
+	actual, err = NewVariableNode("foo.baz").Type(scope)
+	assert.ErrorEqual(t, errors.New("type check failed: unknown field baz in foo.baz"), err)
+	assert.Equal(t, "any", actual.String())
+}
+
+func TestVariableNamedAfterFunction(t *testing.T) {
+	n := NewVariableNode("pi")
+	n.Function = true
+
+	actual, err := n.Eval(map[string]any{"pi": 3})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, actual)
+
+	_, err = n.Eval(nil)
+	assert.ErrorEqual(t, errors.New("node evaluation failed: pi is a function, call it as pi()"), err)
+}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+// Convert converts the value to the given type, following the coercion policy. Lists and objects may be nil, and are
+// converted to []any and map[string]any respectively
+func Convert(t Type, v any, coercion helpers.CoercionPolicy) (any, error) {
+	switch t.Kind {
+	case KindAny:
+		return v, nil
+	case KindNull:
+		if v != nil {
+			return nil, fmt.Errorf("expected null, got %T", v)
+		}
+
+		return nil, nil
+	case KindBool:
+		return coercion.ToBool(v) //nolint:wrapcheck // Error does not need to be wrapped here
+	case KindNumber:
+		// Integers are already numbers, and would lose precision as a float64
+		if rv := reflect.ValueOf(v); rv.CanInt() || rv.CanUint() {
+			return v, nil
+		}
+
+		return coercion.ToFloat64(v) //nolint:wrapcheck // Error does not need to be wrapped here
+	case KindString:
+		return coercion.ToString(v) //nolint:wrapcheck // Error does not need to be wrapped here
+	case KindList:
+		return toList(t.ElemType(), v, coercion)
+	case KindObject:
+		return toObject(v)
+	case KindRegexp:
+		return toRegexp(v)
+	case KindTime:
+		return coercion.ToTime(v) //nolint:wrapcheck // Error does not need to be wrapped here
+	case KindDuration:
+		return coercion.ToDuration(v) //nolint:wrapcheck // Error does not need to be wrapped here
+	case KindLambda:
+		if v == nil || reflect.TypeOf(v).Kind() != reflect.Func {
+			return nil, fmt.Errorf("expected lambda, got %T", v)
+		}
+
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+func toList(elem Type, v any, coercion helpers.CoercionPolicy) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected list, got %T", v)
+	}
+
+	arr, isAny := v.([]any)
+	if isAny && elem.Is(KindAny) {
+		return arr, nil
+	}
+
+	ret := make([]any, rv.Len())
+	for i := range ret {
+		var err error
+		ret[i], err = Convert(elem, rv.Index(i).Interface(), coercion)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+
+	return ret, nil
+}
+
+func toRegexp(v any) (any, error) {
+	switch x := v.(type) {
+	case *regexp.Regexp:
+		return x, nil
+	case string:
+		re, err := regexp.Compile(x)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+
+		return re, nil
+	}
+
+	return nil, fmt.Errorf("expected regular expression, got %T", v)
+}
+
+func toObject(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if obj, ok := v.(map[string]any); ok {
+		return obj, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("expected object, got %T", v)
+	}
+
+	ret := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		ret[iter.Key().String()] = iter.Value().Interface()
+	}
+
+	return ret, nil
+}
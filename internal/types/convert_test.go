@@ -0,0 +1,57 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		t      Type
+		v      any
+		result any
+		err    error
+	}{
+		{Any, []int{1}, []int{1}, nil},
+		{Null, nil, nil, nil},
+		{Null, 1, nil, errors.New("expected null, got int")},
+		{Bool, "true", true, nil},
+		{Bool, 0, false, nil},
+		{Number, "12", float64(12), nil},
+		{Number, int8(12), int8(12), nil},
+		{Number, int64(1) << 60, int64(1) << 60, nil},
+		{Number, "a", nil, errors.New("error parsing value as float, could not parse string 'a'")},
+		{String, 12, "12", nil},
+		{ListOf(Any), []any{1, "a"}, []any{1, "a"}, nil},
+		{ListOf(Any), []string{"a", "b"}, []any{"a", "b"}, nil},
+		{ListOf(Number), []string{"1", "2"}, []any{float64(1), float64(2)}, nil},
+		{ListOf(Number), []string{"1", "b"}, nil, errors.New("item 1: error parsing value as float, could not parse string 'b'")},
+		{ListOf(Any), nil, nil, nil},
+		{ListOf(Any), "a", nil, errors.New("expected list, got string")},
+		{ObjectOf(nil), map[string]any{"a": 1}, map[string]any{"a": 1}, nil},
+		{ObjectOf(nil), map[string]int{"a": 1}, map[string]any{"a": 1}, nil},
+		{ObjectOf(nil), nil, nil, nil},
+		{ObjectOf(nil), map[int]int{}, nil, errors.New("expected object, got map[int]int")},
+		{Regexp, "^a", regexp.MustCompile("^a"), nil},
+		{Regexp, regexp.MustCompile("^a"), regexp.MustCompile("^a"), nil},
+		{Regexp, "(", nil, errors.New("invalid regular expression: error parsing regexp: missing closing ): `(`")},
+		{Regexp, 1, nil, errors.New("expected regular expression, got int")},
+		{Lambda, "x", nil, errors.New("expected lambda, got string")},
+		{Lambda, nil, nil, errors.New("expected lambda, got <nil>")},
+		{Type{Kind: Kind(99)}, 1, nil, errors.New("unsupported type kind(99)")},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s_%v", tc.t, tc.v), func(t *testing.T) {
+			actual, err := Convert(tc.t, tc.v, helpers.CoercionLenient)
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, tc.result, actual)
+			}
+		})
+	}
+}
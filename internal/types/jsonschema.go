@@ -0,0 +1,152 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonSchema struct {
+	Type                 json.RawMessage        `json:"type"`
+	Format               string                 `json:"format"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Items                *jsonSchema            `json:"items"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+}
+
+// FromJSONSchema builds a type from a JSON Schema document.
+//
+// Only the structural keywords type, properties, items and additionalProperties are used, along with the date-time
+// and duration formats for strings. Objects with properties are
+// treated as closed unless additionalProperties is set, so that misspelt fields are caught
+func FromJSONSchema(raw []byte) (Type, error) {
+	s := &jsonSchema{}
+	err := json.Unmarshal(raw, s)
+	if err != nil {
+		return Any, fmt.Errorf("%w: invalid json schema: %w", ErrTypeCheckFailed, err)
+	}
+
+	return s.toType()
+}
+
+func (s *jsonSchema) toType() (Type, error) {
+	if s == nil {
+		return Any, nil
+	}
+
+	name, err := s.typeName()
+	if err != nil {
+		return Any, err
+	}
+
+	switch name {
+	case "":
+		if s.Properties != nil {
+			return s.objectType()
+		}
+
+		return Any, nil
+	case "null":
+		return Null, nil
+	case "boolean":
+		return Bool, nil
+	case "number", "integer":
+		return Number, nil
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return Time, nil
+		case "duration":
+			return Duration, nil
+		}
+
+		return String, nil
+	case "array":
+		elem, err := s.Items.toType()
+		if err != nil {
+			return Any, err
+		}
+
+		return ListOf(elem), nil
+	case "object":
+		return s.objectType()
+	}
+
+	return Any, fmt.Errorf("%w: unsupported json schema type %s", ErrTypeCheckFailed, name)
+}
+
+// typeName reads the type keyword, which may be a single name or a list of names. Nullable types are reduced to
+// the non-null type, and anything more ambiguous is left unchecked
+func (s *jsonSchema) typeName() (string, error) {
+	if len(s.Type) == 0 {
+		return "", nil
+	}
+
+	var name string
+	if json.Unmarshal(s.Type, &name) == nil {
+		return name, nil
+	}
+
+	var names []string
+	err := json.Unmarshal(s.Type, &names)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid json schema type: %s", ErrTypeCheckFailed, s.Type)
+	}
+
+	nonNull := []string{}
+	for _, n := range names {
+		if n != "null" {
+			nonNull = append(nonNull, n)
+		}
+	}
+
+	if len(nonNull) == 1 {
+		return nonNull[0], nil
+	}
+
+	return "", nil
+}
+
+func (s *jsonSchema) objectType() (Type, error) {
+	fields := map[string]Type{}
+	for name, prop := range s.Properties {
+		t, err := prop.toType()
+		if err != nil {
+			return Any, fmt.Errorf("%w in property %s", err, name)
+		}
+
+		fields[name] = t
+	}
+
+	t := ObjectOf(fields)
+	if len(s.AdditionalProperties) == 0 {
+		if s.Properties == nil {
+			t.Fields = nil
+		}
+
+		return t, nil
+	}
+
+	var allowed bool
+	if json.Unmarshal(s.AdditionalProperties, &allowed) == nil {
+		if allowed {
+			t = MapOf(Any)
+			t.Fields = fields
+		}
+
+		return t, nil
+	}
+
+	additional := &jsonSchema{}
+	err := json.Unmarshal(s.AdditionalProperties, additional)
+	if err != nil {
+		return Any, fmt.Errorf("%w: invalid additionalProperties: %w", ErrTypeCheckFailed, err)
+	}
+
+	elem, err := additional.toType()
+	if err != nil {
+		return Any, err
+	}
+	t.Elem = &elem
+
+	return t, nil
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestFromJSONSchema(t *testing.T) {
+	testCases := []struct {
+		name   string
+		schema string
+		path   []string
+		result string
+		err    error
+	}{
+		{"string", `{"type": "object", "properties": {"a": {"type": "string"}}}`, []string{"a"}, "string", nil},
+		{"integer", `{"type": "object", "properties": {"a": {"type": "integer"}}}`, []string{"a"}, "number", nil},
+		{"nullable", `{"type": "object", "properties": {"a": {"type": ["boolean", "null"]}}}`, []string{"a"}, "bool", nil},
+		{"union", `{"type": "object", "properties": {"a": {"type": ["boolean", "string"]}}}`, []string{"a"}, "any", nil},
+		{"array", `{"type": "object", "properties": {"a": {"type": "array", "items": {"type": "number"}}}}`, []string{"a"}, "list<number>", nil},
+		{"nested", `{"properties": {"a": {"properties": {"b": {"type": "null"}}}}}`, []string{"a", "b"}, "null", nil},
+		{"untyped", `{"type": "object", "properties": {"a": {}}}`, []string{"a"}, "any", nil},
+		{"open object", `{"type": "object"}`, []string{"a"}, "any", nil},
+		{"additional allowed", `{"type": "object", "properties": {}, "additionalProperties": true}`, []string{"a"}, "any", nil},
+		{"additional schema", `{"type": "object", "additionalProperties": {"type": "string"}}`, []string{"a"}, "string", nil},
+		{"closed", `{"type": "object", "properties": {"a": {"type": "string"}}}`, []string{"b"}, "any", errors.New("type check failed: unknown field b in b")},
+		{"additional denied", `{"type": "object", "additionalProperties": false}`, []string{"b"}, "any", errors.New("type check failed: unknown field b in b")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			typ, err := FromJSONSchema([]byte(tc.schema))
+			assert.Nil(t, err)
+
+			actual, err := typ.Path(tc.path)
+			assert.Equal(t, tc.result, actual.String())
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestFromJSONSchemaErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		schema string
+		err    error
+	}{
+		{"invalid json", `{`, errors.New("type check failed: invalid json schema: unexpected end of JSON input")},
+		{"invalid type", `{"type": 12}`, errors.New("type check failed: invalid json schema type: 12")},
+		{"unknown type", `{"type": "tuple"}`, errors.New("type check failed: unsupported json schema type tuple")},
+		{"property", `{"properties": {"a": {"type": "tuple"}}}`, errors.New("type check failed: unsupported json schema type tuple in property a")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := FromJSONSchema([]byte(tc.schema))
+			assert.ErrorEqual(t, tc.err, err)
+			assert.ErrorIs(t, ErrTypeCheckFailed, err)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/decimal"
+)
+
+// FromGo builds a type describing values of the given Go type. Struct fields are named using their json tags where present
+func FromGo(t reflect.Type) (Type, error) {
+	return fromGo(t, map[reflect.Type]bool{})
+}
+
+func fromGo(t reflect.Type, seen map[reflect.Type]bool) (Type, error) {
+	if t == nil {
+		return Any, nil
+	}
+
+	switch t {
+	case reflect.TypeFor[*regexp.Regexp](), reflect.TypeFor[regexp.Regexp]():
+		return Regexp, nil
+	case reflect.TypeFor[time.Time](), reflect.TypeFor[*time.Time]():
+		return Time, nil
+	case reflect.TypeFor[time.Duration]():
+		return Duration, nil
+	case reflect.TypeFor[decimal.Decimal]():
+		return Number, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return fromGo(t.Elem(), seen)
+	case reflect.Interface:
+		return Any, nil
+	case reflect.Bool:
+		return Bool, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return Number, nil
+	case reflect.String:
+		return String, nil
+	case reflect.Slice, reflect.Array:
+		elem, err := fromGo(t.Elem(), seen)
+		if err != nil {
+			return Any, err
+		}
+
+		return ListOf(elem), nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return Any, fmt.Errorf("%w: map keys must be strings, got %s", ErrTypeCheckFailed, t.Key())
+		}
+
+		elem, err := fromGo(t.Elem(), seen)
+		if err != nil {
+			return Any, err
+		}
+
+		return MapOf(elem), nil
+	case reflect.Struct:
+		// Recursive types can't be described up front so fall back to any
+		if seen[t] {
+			return Any, nil
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		fields := map[string]Type{}
+		err := structFields(t, fields, seen)
+		if err != nil {
+			return Any, err
+		}
+
+		return ObjectOf(fields), nil
+	case reflect.Func:
+		// Lambdas take a single argument and return a value and an error
+		if t.NumIn() == 1 && t.NumOut() == 2 {
+			return Lambda, nil
+		}
+	case reflect.Invalid, reflect.Uintptr, reflect.Complex64, reflect.Complex128,
+		reflect.Chan, reflect.UnsafePointer:
+	}
+
+	return Any, fmt.Errorf("%w: unsupported go type %s", ErrTypeCheckFailed, t)
+}
+
+func structFields(t reflect.Type, fields map[string]Type, seen map[reflect.Type]bool) error {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, tagged := FieldName(f)
+		if name == "-" {
+			continue
+		}
+
+		// Untagged embedded structs have their fields promoted, matching encoding/json
+		if f.Anonymous && !tagged {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				err := structFields(embedded, fields, seen)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		ft, err := fromGo(f.Type, seen)
+		if err != nil {
+			return fmt.Errorf("%w in field %s", err, f.Name)
+		}
+
+		fields[name] = ft
+	}
+
+	return nil
+}
+
+// FieldName returns the name of the struct field as it appears in expressions, taken from its json tag if there is
+// one, and whether a tag was used
+func FieldName(f reflect.StructField) (string, bool) {
+	tag, ok := f.Tag.Lookup("json")
+	if !ok {
+		return f.Name, false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return f.Name, false
+	}
+
+	return name, true
+}
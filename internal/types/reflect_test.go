@@ -0,0 +1,69 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+type testBase struct {
+	ID int `json:"id"`
+}
+
+type testUser struct {
+	testBase
+	Name     string            `json:"name"`
+	Email    *string           `json:"email,omitempty"`
+	Admin    bool              `json:"admin"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Extra    any               `json:"extra"`
+	Ignored  string            `json:"-"`
+	Untagged float64
+	Parent   *testUser      `json:"parent"`
+	Pattern  *regexp.Regexp `json:"pattern"`
+}
+
+func TestFromGo(t *testing.T) {
+	actual, err := FromGo(reflect.TypeOf(testUser{}))
+	assert.Nil(t, err)
+	assert.Equal(t, KindObject, actual.Kind)
+
+	expected := map[string]string{
+		"id":       "number",
+		"name":     "string",
+		"email":    "string",
+		"admin":    "bool",
+		"tags":     "list<string>",
+		"labels":   "map<string>",
+		"extra":    "any",
+		"Untagged": "number",
+		"parent":   "any",
+		"pattern":  "regexp",
+	}
+	assert.Equal(t, len(expected), len(actual.Fields))
+	for name, typ := range expected {
+		assert.Equal(t, typ, actual.Fields[name].String())
+	}
+}
+
+func TestFromGoErrors(t *testing.T) {
+	testCases := []struct {
+		v   any
+		err error
+	}{
+		{map[int]string{}, errors.New("type check failed: map keys must be strings, got int")},
+		{struct{ C chan int }{}, errors.New("type check failed: unsupported go type chan int in field C")},
+		{[]func(){}, errors.New("type check failed: unsupported go type func()")},
+	}
+	for _, tc := range testCases {
+		t.Run(reflect.TypeOf(tc.v).String(), func(t *testing.T) {
+			_, err := FromGo(reflect.TypeOf(tc.v))
+			assert.ErrorEqual(t, tc.err, err)
+			assert.ErrorIs(t, ErrTypeCheckFailed, err)
+		})
+	}
+}
@@ -0,0 +1,363 @@
+// Package types describes the shape of values so that expressions can be checked ahead of evaluation
+package types
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+const (
+	// ErrTypeCheckFailed is returned when an expression is not valid for the types it will be evaluated against
+	ErrTypeCheckFailed = helpers.ConstError("type check failed")
+
+	// ErrInvalidArity is returned when a function is called with the wrong number of arguments
+	ErrInvalidArity = helpers.ConstError("invalid number of arguments")
+
+	// ErrInvalidArgument is returned when an argument can't be converted to the type a function expects
+	ErrInvalidArgument = helpers.ConstError("invalid argument")
+
+	// ErrInvalidSignature is returned when a signature can't be called as described, such as when a required
+	// parameter follows an optional one
+	ErrInvalidSignature = helpers.ConstError("invalid signature")
+)
+
+// Kind is the broad category a value falls in to
+type Kind int
+
+const (
+	// KindAny matches every value, nothing is checked against it
+	KindAny Kind = iota
+	// KindNull is the absence of a value
+	KindNull
+	// KindBool is a boolean value
+	KindBool
+	// KindNumber is any numeric value
+	KindNumber
+	// KindString is a string value
+	KindString
+	// KindList is a slice or array of values
+	KindList
+	// KindObject is a map or struct of named values
+	KindObject
+	// KindRegexp is a compiled regular expression, strings are compiled when converted to it
+	KindRegexp
+	// KindTime is an instant in time, strings are parsed as RFC3339 when converted to it
+	KindTime
+	// KindDuration is an amount of time, strings such as "15m" are parsed when converted to it
+	KindDuration
+	// KindLambda is a function of a single argument, written as x => expression
+	KindLambda
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindAny:
+		return "any"
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindList:
+		return "list"
+	case KindObject:
+		return "object"
+	case KindRegexp:
+		return "regexp"
+	case KindTime:
+		return "time"
+	case KindDuration:
+		return "duration"
+	case KindLambda:
+		return "lambda"
+	}
+
+	return fmt.Sprintf("kind(%d)", int(k))
+}
+
+// Type describes the shape of a value.
+//
+// - Lists use Elem to describe their items
+// - Objects use Fields to describe their known fields, and Elem to describe any others
+// - Objects without Fields or Elem allow any field
+type Type struct {
+	Kind   Kind
+	Elem   *Type
+	Fields map[string]Type
+}
+
+var (
+	// Any is the type of a value which is not known ahead of evaluation
+	Any = Type{Kind: KindAny}
+	// Null is the type of nil
+	Null = Type{Kind: KindNull}
+	// Bool is the type of a boolean value
+	Bool = Type{Kind: KindBool}
+	// Number is the type of a numeric value
+	Number = Type{Kind: KindNumber}
+	// String is the type of a string value
+	String = Type{Kind: KindString}
+	// Regexp is the type of a compiled regular expression
+	Regexp = Type{Kind: KindRegexp}
+	// Time is the type of an instant in time
+	Time = Type{Kind: KindTime}
+	// Duration is the type of an amount of time
+	Duration = Type{Kind: KindDuration}
+	// Lambda is the type of a function of a single argument
+	Lambda = Type{Kind: KindLambda}
+)
+
+// ListOf creates a list type with items of the given type
+func ListOf(elem Type) Type {
+	return Type{Kind: KindList, Elem: &elem}
+}
+
+// ObjectOf creates an object type with only the given fields
+func ObjectOf(fields map[string]Type) Type {
+	if fields == nil {
+		fields = map[string]Type{}
+	}
+
+	return Type{Kind: KindObject, Fields: fields}
+}
+
+// MapOf creates an object type with any field names, all of the given type
+func MapOf(elem Type) Type {
+	return Type{Kind: KindObject, Elem: &elem}
+}
+
+// Is checks whether the kind of the type is one of those given
+func (t Type) Is(kinds ...Kind) bool {
+	return slices.Contains(kinds, t.Kind)
+}
+
+// ElemType returns the type of the items in a list or the additional fields of an object
+func (t Type) ElemType() Type {
+	if t.Elem == nil {
+		return Any
+	}
+
+	return *t.Elem
+}
+
+// Field returns the type of the named field
+func (t Type) Field(name string) (Type, error) {
+	switch t.Kind {
+	case KindAny:
+		return Any, nil
+	case KindObject:
+		if f, ok := t.Fields[name]; ok {
+			return f, nil
+		}
+
+		if t.Elem != nil || t.Fields == nil {
+			return t.ElemType(), nil
+		}
+
+		return Any, fmt.Errorf("%w: unknown field %s", ErrTypeCheckFailed, name)
+	case KindNull, KindBool, KindNumber, KindString, KindList, KindRegexp, KindTime, KindDuration, KindLambda:
+	}
+
+	return Any, fmt.Errorf("%w: cannot access field %s of %s", ErrTypeCheckFailed, name, t)
+}
+
+// Path follows the given field names through nested objects, returning the type found at the end
+func (t Type) Path(path []string) (Type, error) {
+	current := t
+	for i, name := range path {
+		var err error
+		current, err = current.Field(name)
+		if err != nil {
+			return Any, fmt.Errorf("%w in %s", err, strings.Join(path[:i+1], "."))
+		}
+	}
+
+	return current, nil
+}
+
+// With returns an object type which has the named field in addition to those of this type, objects which allow any
+// field continue to do so
+func (t Type) With(name string, field Type) Type {
+	switch t.Kind {
+	case KindAny:
+		return t
+	case KindObject:
+		ret := Type{Kind: KindObject, Elem: t.Elem, Fields: maps.Clone(t.Fields)}
+		if ret.Fields == nil {
+			ret.Fields = map[string]Type{}
+			if ret.Elem == nil {
+				ret.Elem = &Any
+			}
+		}
+
+		ret.Fields[name] = field
+		return ret
+	case KindNull, KindBool, KindNumber, KindString, KindList, KindRegexp, KindTime, KindDuration, KindLambda:
+	}
+
+	return ObjectOf(map[string]Type{name: field})
+}
+
+// AssignableTo checks whether a value of this type can be used where the target type is expected
+func (t Type) AssignableTo(target Type) bool {
+	if t.Kind == KindAny || target.Kind == KindAny {
+		return true
+	}
+
+	// Strings are compiled or parsed when passed as a regular expression, time or duration
+	if t.Kind == KindString && target.Is(KindRegexp, KindTime, KindDuration) {
+		return true
+	}
+
+	if t.Kind != target.Kind {
+		return false
+	}
+
+	if t.Kind == KindList {
+		return t.ElemType().AssignableTo(target.ElemType())
+	}
+
+	return true
+}
+
+// String returns a readable representation of the type
+func (t Type) String() string {
+	switch t.Kind {
+	case KindList:
+		return fmt.Sprintf("list<%s>", t.ElemType())
+	case KindObject:
+		if len(t.Fields) == 0 && t.Elem != nil {
+			return fmt.Sprintf("map<%s>", t.Elem)
+		}
+
+		return "object"
+	case KindAny, KindNull, KindBool, KindNumber, KindString, KindRegexp, KindTime, KindDuration, KindLambda:
+	}
+
+	return t.Kind.String()
+}
+
+// Param describes a single parameter of a function. Optional parameters must come after all required ones
+type Param struct {
+	Name     string
+	Type     Type
+	Optional bool
+}
+
+// Signature describes the parameters and return type of a function. When Variadic is set the last parameter may be
+// repeated any number of times
+type Signature struct {
+	Params   []Param
+	Variadic bool
+	Returns  Type
+}
+
+// Arity returns the minimum and maximum number of arguments the function accepts, a maximum of -1 means there is no limit
+func (s *Signature) Arity() (minimum, maximum int) {
+	for _, p := range s.Params {
+		if !p.Optional {
+			minimum++
+		}
+	}
+
+	maximum = len(s.Params)
+	if s.Variadic {
+		maximum = -1
+		// The repeated parameter may be left out entirely
+		if len(s.Params) > 0 && !s.Params[len(s.Params)-1].Optional {
+			minimum--
+		}
+	}
+
+	return minimum, maximum
+}
+
+// Validate checks that the signature can be called as described, with every optional parameter after the required
+// ones. The repeated parameter of a variadic function may be left out, so it can follow an optional parameter
+func (s *Signature) Validate(name string) error {
+	last := len(s.Params)
+	if s.Variadic {
+		last--
+	}
+
+	for i := 1; i < last; i++ {
+		if s.Params[i-1].Optional && !s.Params[i].Optional {
+			return fmt.Errorf("%w: function %s has required parameter %s after optional parameter %s", ErrInvalidSignature, name, s.Params[i].Name, s.Params[i-1].Name)
+		}
+	}
+
+	return nil
+}
+
+// CheckArity validates the number of arguments passed to the function
+func (s *Signature) CheckArity(name string, count int) error {
+	minimum, maximum := s.Arity()
+	if count >= minimum && (maximum == -1 || count <= maximum) {
+		return nil
+	}
+
+	expected := fmt.Sprint(minimum)
+	switch {
+	case maximum == -1:
+		expected = fmt.Sprintf("at least %d", minimum)
+	case minimum != maximum:
+		expected = fmt.Sprintf("%d to %d", minimum, maximum)
+	}
+
+	return fmt.Errorf("%w: function %s expects %s arguments, got %d", ErrInvalidArity, name, expected, count)
+}
+
+// Param returns the parameter the argument at the given index will be passed to
+func (s *Signature) Param(i int) Param {
+	if len(s.Params) == 0 {
+		return Param{Type: Any}
+	}
+
+	if i >= len(s.Params) {
+		return s.Params[len(s.Params)-1]
+	}
+
+	return s.Params[i]
+}
+
+// Check validates the argument types against the signature, returning the type the function will produce
+func (s *Signature) Check(name string, args []Type) (Type, error) {
+	err := s.CheckArity(name, len(args))
+	if err != nil {
+		return Any, fmt.Errorf("%w: %w", ErrTypeCheckFailed, err)
+	}
+
+	for i, arg := range args {
+		if p := s.Param(i); !arg.AssignableTo(p.Type) {
+			return Any, fmt.Errorf("%w: argument %d to function %s must be %s, got %s", ErrTypeCheckFailed, i, name, p.Type, arg)
+		}
+	}
+
+	return s.Returns, nil
+}
+
+// Convert converts the arguments to the types of the parameters they will be passed to, following the coercion policy
+func (s *Signature) Convert(name string, args []any, coercion helpers.CoercionPolicy) ([]any, error) {
+	err := s.CheckArity(name, len(args))
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]any, len(args))
+	for i, arg := range args {
+		ret[i], err = Convert(s.Param(i).Type, arg, coercion)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d to function %s: %w", ErrInvalidArgument, i, name, err)
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,239 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+func TestPath(t *testing.T) {
+	scope := ObjectOf(map[string]Type{
+		"name": String,
+		"user": ObjectOf(map[string]Type{"id": Number}),
+		"tags": ListOf(String),
+		"meta": MapOf(Number),
+		"raw":  Any,
+		"open": {Kind: KindObject},
+	})
+
+	testCases := []struct {
+		path   []string
+		result Type
+		err    error
+	}{
+		{[]string{"name"}, String, nil},
+		{[]string{"user", "id"}, Number, nil},
+		{[]string{"tags"}, ListOf(String), nil},
+		{[]string{"meta", "anything"}, Number, nil},
+		{[]string{"raw", "a", "b"}, Any, nil},
+		{[]string{"open", "a"}, Any, nil},
+		{[]string{"nme"}, Any, errors.New("type check failed: unknown field nme in nme")},
+		{[]string{"user", "name"}, Any, errors.New("type check failed: unknown field name in user.name")},
+		{[]string{"name", "first"}, Any, errors.New("type check failed: cannot access field first of string in name.first")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.result.String(), func(t *testing.T) {
+			actual, err := scope.Path(tc.path)
+			assert.Equal(t, tc.result.String(), actual.String())
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrTypeCheckFailed, err)
+			}
+		})
+	}
+}
+
+func TestAssignableTo(t *testing.T) {
+	testCases := []struct {
+		a, b   Type
+		result bool
+	}{
+		{Any, Number, true},
+		{Number, Any, true},
+		{Number, Number, true},
+		{String, Number, false},
+		{Null, String, false},
+		{ListOf(Number), ListOf(Any), true},
+		{ListOf(Number), ListOf(Number), true},
+		{ListOf(String), ListOf(Number), false},
+		{ObjectOf(nil), MapOf(String), true},
+		{String, Regexp, true},
+		{Regexp, String, false},
+		{Lambda, Lambda, true},
+		{String, Lambda, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a.String()+"_"+tc.b.String(), func(t *testing.T) {
+			assert.Equal(t, tc.result, tc.a.AssignableTo(tc.b))
+		})
+	}
+}
+
+func TestSignatureCheck(t *testing.T) {
+	sig := &Signature{Params: []Param{{Name: "x", Type: Number}, {Name: "y", Type: String}}, Returns: Bool}
+
+	testCases := []struct {
+		name   string
+		args   []Type
+		result Type
+		err    error
+	}{
+		{"valid", []Type{Number, String}, Bool, nil},
+		{"any", []Type{Any, Any}, Bool, nil},
+		{"too few", []Type{Number}, Any, errors.New("type check failed: invalid number of arguments: function foo expects 2 arguments, got 1")},
+		{"too many", []Type{Number, String, String}, Any, errors.New("type check failed: invalid number of arguments: function foo expects 2 arguments, got 3")},
+		{"wrong type", []Type{String, String}, Any, errors.New("type check failed: argument 0 to function foo must be number, got string")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := sig.Check("foo", tc.args)
+			assert.Equal(t, tc.result.String(), actual.String())
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	testCases := []struct {
+		t      Type
+		result string
+	}{
+		{Any, "any"},
+		{Null, "null"},
+		{Bool, "bool"},
+		{Number, "number"},
+		{String, "string"},
+		{ListOf(String), "list<string>"},
+		{ListOf(ListOf(Number)), "list<list<number>>"},
+		{ObjectOf(nil), "object"},
+		{MapOf(Bool), "map<bool>"},
+		{Type{Kind: Kind(99)}, "kind(99)"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.result, func(t *testing.T) {
+			assert.Equal(t, tc.result, tc.t.String())
+		})
+	}
+}
+
+func TestCheckArity(t *testing.T) {
+	required := Param{Name: "a", Type: Any}
+	optional := Param{Name: "b", Type: Any, Optional: true}
+
+	testCases := []struct {
+		name  string
+		sig   Signature
+		count int
+		err   error
+	}{
+		{"exact", Signature{Params: []Param{required}}, 1, nil},
+		{"exact too few", Signature{Params: []Param{required}}, 0, errors.New("invalid number of arguments: function foo expects 1 arguments, got 0")},
+		{"none", Signature{}, 0, nil},
+		{"none too many", Signature{}, 1, errors.New("invalid number of arguments: function foo expects 0 arguments, got 1")},
+		{"optional given", Signature{Params: []Param{required, optional}}, 2, nil},
+		{"optional missing", Signature{Params: []Param{required, optional}}, 1, nil},
+		{"optional too many", Signature{Params: []Param{required, optional}}, 3, errors.New("invalid number of arguments: function foo expects 1 to 2 arguments, got 3")},
+		{"variadic none", Signature{Params: []Param{required}, Variadic: true}, 0, nil},
+		{"variadic many", Signature{Params: []Param{required}, Variadic: true}, 5, nil},
+		{"variadic after required", Signature{Params: []Param{required, required}, Variadic: true}, 0, errors.New("invalid number of arguments: " +
+			"function foo expects at least 1 arguments, got 0")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sig.CheckArity("foo", tc.count)
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrInvalidArity, err)
+			}
+		})
+	}
+}
+
+func TestSignatureValidate(t *testing.T) {
+	required := Param{Name: "a", Type: Any}
+	optional := Param{Name: "b", Type: Any, Optional: true}
+
+	testCases := []struct {
+		name string
+		sig  Signature
+		err  error
+	}{
+		{"none", Signature{}, nil},
+		{"required then optional", Signature{Params: []Param{required, optional, optional}}, nil},
+		{"variadic after optional", Signature{Params: []Param{required, optional, required}, Variadic: true}, nil},
+		{"required after optional", Signature{Params: []Param{optional, required}}, errors.New("invalid signature: function foo has required parameter a after optional parameter b")},
+		{"variadic required after optional", Signature{Params: []Param{optional, required, required}, Variadic: true}, errors.New("invalid signature: " +
+			"function foo has required parameter a after optional parameter b")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sig.Validate("foo")
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrInvalidSignature, err)
+			}
+		})
+	}
+}
+
+func TestSignatureConvert(t *testing.T) {
+	sig := &Signature{
+		Params: []Param{
+			{Name: "x", Type: Number},
+			{Name: "y", Type: Bool, Optional: true},
+			{Name: "z", Type: String},
+		},
+		Variadic: true,
+	}
+
+	actual, err := sig.Convert("foo", []any{"1.5"}, helpers.CoercionLenient)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{1.5}, actual)
+
+	actual, err = sig.Convert("foo", []any{1, "yes", 2, 3}, helpers.CoercionLenient)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{float64(1), true, "2", "3"}, actual)
+
+	_, err = sig.Convert("foo", []any{1, "maybe"}, helpers.CoercionLenient)
+	assert.ErrorEqual(t, errors.New("invalid argument 1 to function foo: error parsing value as bool, could not parse string 'maybe'"), err)
+	assert.ErrorIs(t, ErrInvalidArgument, err)
+
+	_, err = sig.Convert("foo", []any{}, helpers.CoercionLenient)
+	assert.ErrorIs(t, ErrInvalidArity, err)
+
+	_, err = sig.Convert("foo", []any{"1.5"}, helpers.CoercionStrict)
+	assert.ErrorEqual(t, errors.New("invalid argument 0 to function foo: error parsing value as float, invalid type: string"), err)
+
+	actual, err = sig.Convert("foo", []any{"", "", nil}, helpers.CoercionJS)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{float64(0), false, "null"}, actual)
+}
+
+func TestWith(t *testing.T) {
+	testCases := []struct {
+		name   string
+		t      Type
+		path   string
+		result string
+		err    error
+	}{
+		{"any", Any, "x.y", "any", nil},
+		{"closed object", ObjectOf(map[string]Type{"a": Number}), "x", "string", nil},
+		{"closed object keeps fields", ObjectOf(map[string]Type{"a": Number}), "a", "number", nil},
+		{"closed object stays closed", ObjectOf(map[string]Type{"a": Number}), "b", "any", errors.New("type check failed: unknown field b in b")},
+		{"open object stays open", Type{Kind: KindObject}, "b", "any", nil},
+		{"map", MapOf(Bool), "b", "bool", nil},
+		{"map param", MapOf(Bool), "x", "string", nil},
+		{"not an object", Number, "x", "string", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := tc.t.With("x", String).Path(strings.Split(tc.path, "."))
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual.String())
+		})
+	}
+}
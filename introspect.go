@@ -0,0 +1,132 @@
+package parsley
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// FunctionDoc is help text for a function, for building documentation and autocomplete
+type FunctionDoc struct {
+	// Description says what the function does
+	Description string
+	// Examples are expressions which call the function
+	Examples []string
+}
+
+// FunctionInfo describes a registered function
+type FunctionInfo struct {
+	Name string
+	// Signature is nil if the function was registered without one
+	Signature *Signature
+	FunctionDoc
+}
+
+// OperatorInfo describes an operator. A token used as both a unary and a binary operator, such as -, is listed twice
+type OperatorInfo struct {
+	// Token is the operator, or the first keyword of a node with several operands
+	Token string
+	// Arity is 1 for unary operators, 2 for binary operators and the number of operands for nodes with several
+	Arity int
+	// Postfix is set for unary operators which follow their operand
+	Postfix bool
+}
+
+// Describe attaches help text to a function, see Parser.Describe
+func (r *Registry) Describe(name string, doc FunctionDoc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.current.Load()
+	if _, ok := current.functions[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrFunctionNotFound, name)
+	}
+
+	// Docs don't change how expressions are parsed, so the version is kept and cached expressions stay valid
+	s := *current
+	s.docs = maps.Clone(current.docs)
+	s.docs[name] = doc
+	r.current.Store(&s)
+
+	return nil
+}
+
+// Describe attaches help text to a registered function, which is returned by Functions. The text is removed when the
+// function is registered again or unregistered. ErrFunctionNotFound is returned when the function isn't registered
+func (p *Parser) Describe(name string, doc FunctionDoc) error {
+	return p.Registry.Describe(name, doc)
+}
+
+// Functions returns the registered functions sorted by name
+func (r *Registry) Functions() []FunctionInfo {
+	s := r.load()
+	ret := make([]FunctionInfo, 0, len(s.functions))
+	for _, name := range slices.Sorted(maps.Keys(s.functions)) {
+		ret = append(ret, FunctionInfo{Name: name, Signature: s.signature(name), FunctionDoc: s.docs[name]})
+	}
+
+	return ret
+}
+
+// Functions returns the functions available to expressions sorted by name
+func (p *Parser) Functions() []FunctionInfo {
+	return p.Registry.Functions()
+}
+
+// Operators returns the built-in and registered operators sorted by token
+func (r *Registry) Operators() []OperatorInfo {
+	s := r.load()
+
+	var ret []OperatorInfo
+	add := func(token string, arity int) {
+		op := OperatorInfo{Token: token, Arity: arity}
+		if !slices.Contains(ret, op) {
+			ret = append(ret, op)
+		}
+	}
+
+	for token := range s.postfix {
+		ret = append(ret, OperatorInfo{Token: token, Arity: 1, Postfix: true})
+	}
+
+	for token, n := range s.nary {
+		add(token, len(n.keywords)+1)
+	}
+
+	add("+", 1)
+	add("-", 1)
+	for token := range s.unaryNodes {
+		add(token, 1)
+	}
+
+	for token := range builtinPrecedence {
+		add(token, 2)
+	}
+
+	for token := range s.binaryNodes {
+		add(token, 2)
+	}
+
+	slices.SortFunc(ret, func(a, b OperatorInfo) int {
+		return cmp.Or(cmp.Compare(a.Token, b.Token), cmp.Compare(a.Arity, b.Arity), compareBool(a.Postfix, b.Postfix))
+	})
+
+	return ret
+}
+
+// Operators returns the operators available to expressions sorted by token
+func (p *Parser) Operators() []OperatorInfo {
+	return p.Registry.Operators()
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+
+	return -1
+}
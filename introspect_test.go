@@ -0,0 +1,115 @@
+package parsley
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestUnregister(t *testing.T) {
+	parser, err := New(WithCache())
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.RegisterUnaryNode("£", func(_ Node) Node { return &testNode{} })
+	parser.RegisterBinaryNode("£", func(_, _ Node) Node { return &testNode{} })
+	parser.RegisterBinaryNode("-", func(_, _ Node) Node { return &testNode{} })
+
+	// Parse everything first so that the cache has to be invalidated
+	for _, input := range []string{`contains_any(a, "b", 1)`, `£1`, `1£1`, `3-1`} {
+		_, err := parser.ParseAsAny(input, nil)
+		assert.Nil(t, err)
+	}
+
+	parser.UnregisterFunction("contains_any")
+	parser.UnregisterUnaryNode("£")
+	parser.UnregisterBinaryNode("-")
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`contains_any(a, "b", 1)`, nil, errors.New("function not found: contains_any")},
+		{`£1`, nil, errors.New("unexpected token: £")},
+		{`1£1`, 12, nil},
+		{`3-1`, float64(2), nil},
+		{`-1`, float64(-1), nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, nil)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+
+	parser.UnregisterBinaryNode("£")
+	_, err = parser.ParseAsAny(`1£1`, nil)
+	assert.ErrorEqual(t, errors.New("unexpected character: £"), err)
+}
+
+func TestFunctions(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterFunction("double", func(args ...any) (any, error) { return args[0], nil }, Signature{
+		Params:  []Param{{Name: "x", Type: TypeNumber}},
+		Returns: TypeNumber,
+	})
+	version := r.load().version
+	err := r.Describe("double", FunctionDoc{Description: "Doubles a number", Examples: []string{"double(2)"}})
+	assert.Nil(t, err)
+	assert.Equal(t, version, r.load().version)
+	err = r.Describe("ceil", FunctionDoc{Description: "Rounds up"})
+	assert.Nil(t, err)
+	r.UnregisterFunction("contains_any")
+
+	version = r.load().version
+	err = r.Describe("missing", FunctionDoc{Description: "Not registered"})
+	assert.ErrorEqual(t, errors.New("function not found: missing"), err)
+	assert.ErrorIs(t, ErrFunctionNotFound, err)
+	_, ok := r.load().docs["missing"]
+	assert.Equal(t, false, ok)
+	assert.Equal(t, version, r.load().version)
+
+	functions := r.Functions()
+	names := []string{}
+	for _, f := range functions {
+		names = append(names, f.Name)
+	}
+
+	assert.Equal(t, []string{"absolute", "ceil", "double", "floor", "is_null", "not", "round", "truncate"}, names)
+	assert.Equal(t, "Rounds up", functions[1].Description)
+	assert.Equal(t, true, functions[1].Signature != nil)
+	assert.Equal(t, "Doubles a number", functions[2].Description)
+	assert.Equal(t, []string{"double(2)"}, functions[2].Examples)
+	assert.Equal(t, "x", functions[2].Signature.Params[0].Name)
+
+	// Registering again replaces the description
+	r.RegisterFunction("double", func(args ...any) (any, error) { return args[0], nil })
+	functions = r.Functions()
+	assert.Equal(t, "", functions[2].Description)
+	assert.Equal(t, true, functions[2].Signature == nil)
+}
+
+func TestOperators(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.RegisterUnaryNode("£", func(_ Node) Node { return &testNode{} })
+	parser.RegisterBinaryNode("£", func(_, _ Node) Node { return &testNode{} })
+	parser.RegisterBinaryNode("+", func(_, _ Node) Node { return &testNode{} })
+
+	parser.RegisterPostfixNode("!", func(_ Node) Node { return &testNode{} })
+	err = parser.RegisterNaryNode([]string{"between", "and"}, func(_ ...Node) Node { return &testNode{} })
+	assert.Nil(t, err)
+
+	expected := []OperatorInfo{
+		{"!", 1, true}, {"!~", 2, false}, {"%", 2, false}, {"&&", 2, false}, {"*", 2, false},
+		{"+", 1, false}, {"+", 2, false}, {"-", 1, false}, {"-", 2, false}, {"/", 2, false},
+		{"<", 2, false}, {"==", 2, false}, {"=~", 2, false}, {">", 2, false}, {"^", 2, false},
+		{"between", 3, false}, {"in", 2, false}, {"||", 2, false}, {"£", 1, false}, {"£", 2, false},
+	}
+	assert.Equal(t, expected, parser.Operators())
+}
@@ -0,0 +1,54 @@
+package parsley
+
+import (
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/nodes"
+)
+
+// Library is an opt-in set of functions which can be loaded in to a parser
+type Library struct {
+	Name     string
+	register func(r FunctionRegistrar)
+}
+
+// environment gives functions access to settings of the parser evaluating them
+type environment struct {
+	now       func() time.Time
+	semantics *nodes.Semantics
+}
+
+// defaultEnvironment is used by functions which aren't bound to a parser
+func defaultEnvironment() environment {
+	return environment{now: time.Now, semantics: &nodes.Semantics{}}
+}
+
+// binder is implemented by registrars which can bind functions to the parser evaluating them
+type binder interface {
+	registerBound(name string, bind func(env environment) Function, sig ...Signature)
+}
+
+// registerBound registers a function which depends on settings of the parser evaluating it, such as the clock or the
+// coercion policy. The function is bound when an expression calling it is parsed, registrars which can't bind
+// functions get one bound to the default environment
+func registerBound(r FunctionRegistrar, name string, bind func(env environment) Function, sig ...Signature) {
+	if b, ok := r.(binder); ok {
+		b.registerBound(name, bind, sig...)
+		return
+	}
+
+	r.RegisterFunction(name, bind(defaultEnvironment()), sig...)
+}
+
+// LoadLibrary registers every function in the given libraries. Functions with the same name as one already
+// registered will replace it
+func (p *Parser) LoadLibrary(libs ...Library) {
+	p.Registry.LoadLibrary(libs...)
+}
+
+// LoadLibrary registers every function in the given libraries, see Parser.LoadLibrary
+func (r *Registry) LoadLibrary(libs ...Library) {
+	for _, lib := range libs {
+		lib.register(r)
+	}
+}
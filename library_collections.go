@@ -0,0 +1,408 @@
+package parsley
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/scottkgregory/parsley/internal/decimal"
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/nodes"
+)
+
+// CollectionLibrary provides functions for working with lists. Many take a lambda, written as x => expression, which
+// is called with each item of the list in turn. Keys used for ordering are compared with the < operator. len is
+// shared with StringLibrary, while find has the same name as the function in RegexpLibrary, so load one of them as a
+// module to use both, see Parser.LoadModule
+var CollectionLibrary = Library{Name: "collections", register: registerCollections}
+
+func registerCollections(r FunctionRegistrar) {
+	registerCollectionFunc(r, "any", collections.anyOf)
+	registerCollectionFunc(r, "all", collections.allOf)
+	registerCollectionFunc(r, "filter", collections.filter)
+	RegisterFunc2(r, "map", collectionFunc("map", mapList))
+	registerCollectionFunc(r, "find", collections.find)
+	registerCollectionFunc(r, "min_by", collections.minBy)
+	registerCollectionFunc(r, "max_by", collections.maxBy)
+	registerCollectionFunc(r, "sort_by", collections.sortBy)
+	RegisterFunc1(r, "unique", unique)
+	RegisterFunc1(r, "flatten", flatten)
+	RegisterFunc1(r, "first", func(list []any) (any, error) { return at(list, 0), nil })
+	RegisterFunc1(r, "last", func(list []any) (any, error) { return at(list, len(list)-1), nil })
+	registerLen(r)
+
+	aggregate := Signature{
+		Params: []Param{
+			{Name: "list", Type: ListOf(TypeAny)},
+			{Name: "f", Type: TypeLambda, Optional: true},
+		},
+		Returns: TypeNumber,
+	}
+	registerAggregateFunc(r, "count", collections.count, func(any) (any, error) { return true, nil }, aggregate)
+	registerAggregateFunc(r, "sum", collections.sum, identity, aggregate)
+	registerAggregateFunc(r, "avg", collections.avg, identity, aggregate)
+}
+
+// collections holds the semantics of the parser evaluating a collection function, so that lambda results are
+// converted and compared in the same way as the rest of the expression
+type collections struct {
+	semantics *nodes.Semantics
+}
+
+// registerCollectionFunc registers a function taking a list and a lambda, which is bound to the parser evaluating it
+func registerCollectionFunc[R any](r FunctionRegistrar, name string, fun func(collections, []any, Lambda) (R, error)) {
+	registerBoundFunc2(r, name, func(env environment) func([]any, Lambda) (R, error) {
+		c := collections{env.semantics}
+		return collectionFunc(name, func(list []any, f Lambda) (R, error) { return fun(c, list, f) })
+	})
+}
+
+// registerAggregateFunc registers a function taking a list and an optional lambda, which is bound to the parser
+// evaluating it
+func registerAggregateFunc(r FunctionRegistrar, name string, fun func(collections, []any, Lambda) (any, error), def Lambda, sig Signature) {
+	registerBound(r, name, func(env environment) Function {
+		c := collections{env.semantics}
+		return aggregateFunc(name, func(list []any, f Lambda) (any, error) { return fun(c, list, f) }, def)
+	}, sig)
+}
+
+// collectionFunc wraps errors from the lambda with the name of the function it was passed to
+func collectionFunc[R any](name string, fun func([]any, Lambda) (R, error)) func([]any, Lambda) (R, error) {
+	return func(list []any, f Lambda) (R, error) {
+		ret, err := fun(list, f)
+		if err != nil {
+			return ret, fmt.Errorf("error calling function %s: %w", name, err)
+		}
+
+		return ret, nil
+	}
+}
+
+// aggregateFunc adapts a function taking a list and an optional lambda, which is replaced by def when left out
+func aggregateFunc(name string, fun func([]any, Lambda) (any, error), def Lambda) Function {
+	return func(args ...any) (any, error) {
+		list, _ := args[0].([]any)
+		f := def
+		if len(args) > 1 {
+			f, _ = args[1].(Lambda)
+		}
+
+		ret, err := fun(list, f)
+		if err != nil {
+			return nil, fmt.Errorf("error calling function %s: %w", name, err)
+		}
+
+		return ret, nil
+	}
+}
+
+func identity(x any) (any, error) {
+	return x, nil
+}
+
+// test calls the lambda and converts the result to a bool
+func (c collections) test(f Lambda, x any) (bool, error) {
+	ret, err := f(x)
+	if err != nil {
+		return false, err
+	}
+
+	return c.semantics.Coercion.ToBool(ret) //nolint:wrapcheck // Error is wrapped by the caller
+}
+
+func (c collections) anyOf(list []any, f Lambda) (bool, error) {
+	for _, x := range list {
+		ok, err := c.test(f, x)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+func (c collections) allOf(list []any, f Lambda) (bool, error) {
+	for _, x := range list {
+		ok, err := c.test(f, x)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (c collections) filter(list []any, f Lambda) ([]any, error) {
+	ret := []any{}
+	for _, x := range list {
+		ok, err := c.test(f, x)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			ret = append(ret, x)
+		}
+	}
+
+	return ret, nil
+}
+
+func mapList(list []any, f Lambda) ([]any, error) {
+	ret := make([]any, len(list))
+	for i, x := range list {
+		var err error
+		ret[i], err = f(x)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+func (c collections) find(list []any, f Lambda) (any, error) {
+	for _, x := range list {
+		ok, err := c.test(f, x)
+		if err != nil || ok {
+			return x, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (c collections) count(list []any, f Lambda) (any, error) {
+	n := 0.0
+	for _, x := range list {
+		ok, err := c.test(f, x)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// sum adds up the numbers in the list, exactly when decimal arithmetic is enabled
+func (c collections) sum(list []any, f Lambda) (any, error) {
+	_, exact := c.semantics.DecimalContext()
+	total, sum := 0.0, decimal.FromInt(0)
+	for _, x := range list {
+		v, err := f(x)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := c.semantics.Coercion.ToFloat64(v)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // Error is wrapped by the caller
+		}
+
+		if !exact {
+			total += n
+			continue
+		}
+
+		d, err := decimal.From(v)
+		if err != nil {
+			// Values such as bools aren't decimals, but have already been converted by the coercion policy
+			d, err = decimal.FromFloat(n)
+		}
+		if err != nil {
+			return nil, err //nolint:wrapcheck // Error is wrapped by the caller
+		}
+
+		sum = sum.Add(d)
+	}
+
+	if exact {
+		return sum, nil
+	}
+
+	return total, nil
+}
+
+func (c collections) avg(list []any, f Lambda) (any, error) {
+	if len(list) == 0 {
+		return nil, errors.New("empty list")
+	}
+
+	total, err := c.sum(list, f)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx, exact := c.semantics.DecimalContext(); exact {
+		//nolint:wrapcheck // Error is wrapped by the caller
+		return total.(decimal.Decimal).Div(decimal.FromInt(int64(len(list))), ctx)
+	}
+
+	return total.(float64) / float64(len(list)), nil
+}
+
+// less compares two keys using the < operator, so that numbers, strings, times and durations can all be ordered
+func (c collections) less(a, b any) (bool, error) {
+	ret, err := c.semantics.Calculate("<", a, b)
+	if err != nil {
+		return false, err //nolint:wrapcheck // Error is wrapped by the caller
+	}
+
+	return c.semantics.Coercion.ToBool(ret) //nolint:wrapcheck // Error is wrapped by the caller
+}
+
+func (c collections) minBy(list []any, f Lambda) (any, error) {
+	return c.extremeBy(list, f, false)
+}
+
+func (c collections) maxBy(list []any, f Lambda) (any, error) {
+	return c.extremeBy(list, f, true)
+}
+
+// extremeBy returns the first item with the smallest key, or the largest key when largest is set
+func (c collections) extremeBy(list []any, f Lambda, largest bool) (any, error) {
+	ks, err := mapList(list, f)
+	if err != nil {
+		return nil, err
+	}
+
+	best := -1
+	for i, k := range ks {
+		if best < 0 {
+			best = i
+			continue
+		}
+
+		a, b := k, ks[best]
+		if largest {
+			a, b = b, a
+		}
+
+		better, err := c.less(a, b)
+		if err != nil {
+			return nil, err
+		}
+
+		if better {
+			best = i
+		}
+	}
+
+	return at(list, best), nil
+}
+
+// sortBy returns a copy of the list ordered by key, items with equal keys keep their order
+func (c collections) sortBy(list []any, f Lambda) ([]any, error) {
+	ks, err := mapList(list, f)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, len(list))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	var sortErr error
+	slices.SortStableFunc(indexes, func(i, j int) int {
+		if sortErr != nil {
+			return 0
+		}
+
+		lt, err := c.less(ks[i], ks[j])
+		if err != nil || lt {
+			sortErr = err
+			return -1
+		}
+
+		gt, err := c.less(ks[j], ks[i])
+		if err != nil || gt {
+			sortErr = err
+			return 1
+		}
+
+		return 0
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	ret := make([]any, len(list))
+	for i, index := range indexes {
+		ret[i] = list[index]
+	}
+
+	return ret, nil
+}
+
+// unique returns the list without repeated items, keeping the first of each. Items are compared with deep equality
+func unique(list []any) ([]any, error) {
+	ret := []any{}
+	for _, x := range list {
+		if !slices.ContainsFunc(ret, func(y any) bool { return nodes.Equal(x, y) }) {
+			ret = append(ret, x)
+		}
+	}
+
+	return ret, nil
+}
+
+// flatten joins the items of any lists in the list in to a single list, other items are kept as they are
+func flatten(list []any) ([]any, error) {
+	ret := []any{}
+	for _, x := range list {
+		rv := reflect.ValueOf(x)
+		if x == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+			ret = append(ret, x)
+			continue
+		}
+
+		for i := range rv.Len() {
+			ret = append(ret, rv.Index(i).Interface())
+		}
+	}
+
+	return ret, nil
+}
+
+// at returns the item at the index, or nil when it is out of range
+func at(list []any, i int) any {
+	if i < 0 || i >= len(list) {
+		return nil
+	}
+
+	return list[i]
+}
+
+// registerLen registers len, which is shared by the string and collection libraries
+func registerLen(r FunctionRegistrar) {
+	registerBoundFunc1(r, "len", func(env environment) func(any) (float64, error) { return length(env.semantics.Coercion) })
+}
+
+// length returns the number of items in a list or object, or the number of runes in anything else converted to a
+// string
+func length(coercion helpers.CoercionPolicy) func(any) (float64, error) {
+	return func(v any) (float64, error) {
+		if v == nil {
+			return 0, nil
+		}
+
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array || rv.Kind() == reflect.Map {
+			return float64(rv.Len()), nil
+		}
+
+		s, err := coercion.ToString(v)
+		if err != nil {
+			return 0, fmt.Errorf("error calling function len: %w", err)
+		}
+
+		return float64(utf8.RuneCountInString(s)), nil
+	}
+}
@@ -0,0 +1,216 @@
+package parsley
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestCollectionLibrary(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.LoadLibrary(CollectionLibrary)
+
+	type person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	data := map[string]any{
+		"name": "outer",
+		"builds": []any{
+			map[string]any{"name": "test", "status": "success", "duration": 30.5},
+			map[string]any{"name": "lint", "status": "failed", "duration": 12},
+			map[string]any{"name": "bar", "status": "success", "duration": 61},
+		},
+		"tags":    []string{"b", "a", "b", "c"},
+		"numbers": []int{3, 1, 2},
+		"nested":  []any{[]any{1, 2}, []int{3}, 4},
+		"empty":   []any{},
+		"labels":  map[string]any{"a": 1, "b": 2},
+		"people":  []person{{"ann", 25}, {"bob", 40}},
+	}
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`any(builds, x => x.name == "bar")`, true, nil},
+		{`any(builds, x => x.name == "baz")`, false, nil},
+		{`any(empty, x => true)`, false, nil},
+		{`any(missing, x => true)`, false, nil},
+		{`all(builds, b => b.duration > 10)`, true, nil},
+		{`all(builds, b => b.status == "success")`, false, nil},
+		{`all(empty, x => false)`, true, nil},
+		{`filter(builds, b => b.status == "failed")`, "[map[duration:12 name:lint status:failed]]", nil},
+		{`map(builds, b => b.name)`, "[test lint bar]", nil},
+		{`map(numbers, n => n * 2)`, "[6 2 4]", nil},
+		{`map(builds, b => name)`, "[outer outer outer]", nil},
+		{`map(builds, name => name.status)`, "[success failed success]", nil},
+		{`count(builds)`, float64(3), nil},
+		{`count(builds, b => b.status == "success")`, float64(2), nil},
+		{`find(builds, b => b.duration > 60)`, "map[duration:61 name:bar status:success]", nil},
+		{`find(builds, b => b.duration > 100)`, nil, nil},
+		{`sum(numbers)`, float64(6), nil},
+		{`sum(builds, b => b.duration)`, 103.5, nil},
+		{`avg(numbers)`, float64(2), nil},
+		{`avg(empty)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function avg: empty list")},
+		{`min_by(builds, b => b.duration)`, "map[duration:12 name:lint status:failed]", nil},
+		{`max_by(builds, b => b.duration).name`, nil, errors.New("unexpected characters at end of expression")},
+		{`max_by(builds, b => b.name)`, "map[duration:30.5 name:test status:success]", nil},
+		{`min_by(empty, x => x)`, nil, nil},
+		{`sort_by(numbers, n => n)`, "[1 2 3]", nil},
+		{`sort_by(numbers, n => -n)`, "[3 2 1]", nil},
+		{`map(sort_by(builds, b => b.name), b => b.name)`, "[bar lint test]", nil},
+		{`sort_by(builds, b => b)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function sort_by: " +
+			"error running comparison: cannot order map[string]interface {} and map[string]interface {}")},
+		{`unique(tags)`, "[b a c]", nil},
+		{`unique(flatten(nested))`, "[1 2 3 4]", nil},
+		{`unique(map(numbers, n => n > 1))`, "[true false]", nil},
+		{`flatten(nested)`, "[1 2 3 4]", nil},
+		{`first(tags)`, "b", nil},
+		{`last(tags)`, "c", nil},
+		{`first(empty)`, nil, nil},
+		{`len(tags)`, float64(4), nil},
+		{`len(labels)`, float64(2), nil},
+		{`len("héllo")`, float64(5), nil},
+		{`len(missing)`, float64(0), nil},
+		{`len(123)`, float64(3), nil},
+		{`any(builds, "name")`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 1 to function any: expected lambda, got string")},
+		{`any(builds, x => x.duration > "a")`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function any: " +
+			"node evaluation failed: error running comparison: only one side of comparison was a string: float64 string")},
+		{`x => x`, nil, errors.New("lambda x => ... can only be passed as a function argument")},
+		{`any(builds, 1 + x => x)`, nil, errors.New("lambda x => ... can only be passed as a function argument")},
+		{`any(builds, (x => x))`, nil, errors.New("lambda x => ... can only be passed as a function argument")},
+		{`any(builds, x.name => true)`, nil, errors.New("invalid lambda parameter x.name, parameter names can't contain '.'")},
+		{`map(filter(people, p => p.age > 30), p => p.name)`, "[bob]", nil},
+		{`any(people, p => p.name == name)`, false, nil},
+		{`all(map(builds, b => b.name), n => any(tags, t => t == n))`, false, nil},
+		{`any(map(builds, b => b.name), n => any(builds, b => b.name == n))`, true, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, data)
+			assert.ErrorEqual(t, tc.err, err)
+			if _, ok := tc.result.(string); ok {
+				str, _ := parser.ParseAsString(tc.input, data)
+				assert.Equal(t, tc.result, str)
+				return
+			}
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
+
+func TestCollectionSchema(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.LoadLibrary(CollectionLibrary)
+
+	type build struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	schema, err := SchemaFor(struct {
+		Builds []build `json:"builds"`
+	}{})
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		input  string
+		result string
+		err    error
+	}{
+		{`any(builds, b => b.name == "bar")`, "bool", nil},
+		{`filter(builds, b => b.status == "failed")`, "list<any>", nil},
+		{`count(builds, b => b.status == "failed") > 1`, "bool", nil},
+		{`any(builds, b => b.nme == "bar")`, "any", errors.New("type check failed: unknown field nme in b.nme")},
+		{`any(builds, b => b.name > 3)`, "any", errors.New("type check failed: only one side of comparison was a string: string number")},
+		{`any(builds, "name")`, "any", errors.New("type check failed: argument 1 to function any must be lambda, got string")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.Check(tc.input, schema)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual.String())
+		})
+	}
+}
+
+// namesRegistrar counts how many times each function is registered
+type namesRegistrar map[string]int
+
+func (n namesRegistrar) RegisterFunction(name string, _ Function, _ ...Signature) {
+	n[name]++
+}
+
+func TestLibraryConflicts(t *testing.T) {
+	names := namesRegistrar{}
+	for _, lib := range []Library{CollectionLibrary, MathLibrary, RegexpLibrary, StringLibrary, TimeLibrary} {
+		lib.register(names)
+	}
+
+	conflicts := []string{}
+	for name, n := range names {
+		if n > 1 {
+			conflicts = append(conflicts, name)
+		}
+	}
+
+	slices.Sort(conflicts)
+	assert.Equal(t, []string{"find", "len"}, conflicts)
+
+	data := map[string]any{"tags": []any{"a", "bb"}}
+	for _, libs := range [][]Library{{StringLibrary, CollectionLibrary}, {CollectionLibrary, StringLibrary}} {
+		parser, err := New(WithLibraries(libs...))
+		assert.Nil(t, err)
+
+		parser.LoadModule("re", RegexpLibrary)
+
+		actual, err := parser.ParseAsAny(`len(tags) + len("héllo") + len(find(tags, t => len(t) > 1))`, data)
+		assert.Nil(t, err)
+		assert.Equal(t, float64(9), actual)
+
+		actual, err = parser.ParseAsAny(`re.find("abc", "b+")`, data)
+		assert.Nil(t, err)
+		assert.Equal(t, "b", actual)
+
+		parser.Close()
+	}
+}
+
+// fieldNode is a custom node which reads the named field of the data it is evaluated with
+type fieldNode struct {
+	name string
+}
+
+func (n *fieldNode) Eval(data map[string]any) (any, error) {
+	return data[n.name], nil
+}
+
+func (n *fieldNode) String() string {
+	return "@" + n.name
+}
+
+func TestLambdaCustomNodes(t *testing.T) {
+	parser, err := New(WithLibraries(CollectionLibrary))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.RegisterUnaryNode("@", func(right Node) Node { return &fieldNode{right.String()} })
+
+	data := map[string]any{"name": "outer", "list": []any{"a", "b"}}
+
+	// Custom nodes see the data along with the parameters of the lambdas they are in
+	actual, err := parser.ParseAsString(`map(list, x => @name + @x)`, data)
+	assert.Nil(t, err)
+	assert.Equal(t, "[outera outerb]", actual)
+	assert.Equal(t, 2, len(data))
+}
@@ -0,0 +1,118 @@
+package parsley
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+// MathLibrary provides mathematical functions beyond those registered by default. Calls which would produce NaN or
+// infinity, such as sqrt(-1) or log(0), return an error instead. The constants are functions, so they are written
+// with brackets as in 2 * pi() and e() ^ 2. Leaving the brackets out is an error, unless the data has a variable of
+// the same name
+var MathLibrary = Library{Name: "math", register: registerMath}
+
+func registerMath(r FunctionRegistrar) {
+	RegisterFunc0(r, "pi", func() (float64, error) { return math.Pi, nil })
+	RegisterFunc0(r, "e", func() (float64, error) { return math.E, nil })
+
+	RegisterFunc1(r, "sqrt", checkedMath("sqrt", math.Sqrt))
+	RegisterFunc1(r, "exp", checkedMath("exp", math.Exp))
+	RegisterFunc1(r, "log", checkedMath("log", math.Log))
+	RegisterFunc1(r, "log10", checkedMath("log10", math.Log10))
+	RegisterFunc1(r, "log2", checkedMath("log2", math.Log2))
+	RegisterFunc1(r, "sin", checkedMath("sin", math.Sin))
+	RegisterFunc1(r, "cos", checkedMath("cos", math.Cos))
+	RegisterFunc1(r, "tan", checkedMath("tan", math.Tan))
+	RegisterFunc1(r, "sign", sign)
+	RegisterFunc2(r, "pow", checkedMath2("pow", math.Pow))
+	RegisterFunc2(r, "hypot", checkedMath2("hypot", math.Hypot))
+	RegisterFunc2(r, "round_to", roundTo)
+	RegisterFunc3(r, "clamp", clamp)
+
+	extreme := Signature{
+		Params: []Param{
+			{Name: "x", Type: TypeNumber},
+			{Name: "xs", Type: TypeNumber},
+		},
+		Variadic: true,
+		Returns:  TypeNumber,
+	}
+	registerBound(r, "min", func(env environment) Function { return extremeFunc("min", math.Min, env.semantics.Coercion) }, extreme)
+	registerBound(r, "max", func(env environment) Function { return extremeFunc("max", math.Max, env.semantics.Coercion) }, extreme)
+}
+
+// checkDomain returns an error if the result of a calculation is not a finite number
+func checkDomain(name string, result float64, args ...float64) (float64, error) {
+	if math.IsNaN(result) {
+		return 0, fmt.Errorf("error calling function %s: %v is outside the domain", name, args)
+	}
+
+	if math.IsInf(result, 0) {
+		return 0, fmt.Errorf("error calling function %s: result of %v is out of range", name, args)
+	}
+
+	return result, nil
+}
+
+func checkedMath(name string, fun func(float64) float64) func(float64) (float64, error) {
+	return func(x float64) (float64, error) {
+		return checkDomain(name, fun(x), x)
+	}
+}
+
+func checkedMath2(name string, fun func(float64, float64) float64) func(float64, float64) (float64, error) {
+	return func(x, y float64) (float64, error) {
+		return checkDomain(name, fun(x, y), x, y)
+	}
+}
+
+func sign(x float64) (float64, error) {
+	switch {
+	case x > 0:
+		return 1, nil
+	case x < 0:
+		return -1, nil
+	}
+
+	return 0, nil
+}
+
+func roundTo(x float64, places int) (float64, error) {
+	scale := math.Pow(10, float64(places))
+	return checkDomain("round_to", math.Round(x*scale)/scale, x, float64(places))
+}
+
+func clamp(x, lo, hi float64) (float64, error) {
+	if lo > hi {
+		return 0, fmt.Errorf("error calling function clamp: lower bound %v is greater than upper bound %v", lo, hi)
+	}
+
+	return math.Min(math.Max(x, lo), hi), nil
+}
+
+func extremeFunc(name string, pick func(float64, float64) float64, coercion helpers.CoercionPolicy) Function {
+	return func(args ...any) (any, error) {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%w: function %s expects at least 1 arguments, got 0", ErrInvalidArity, name)
+		}
+
+		var ret float64
+		for i, arg := range args {
+			x, err := convertArg[float64](name, i, arg, coercion)
+			if err != nil {
+				return nil, err
+			}
+
+			if i == 0 {
+				ret = x
+				continue
+			}
+
+			ret = pick(ret, x)
+		}
+
+		return ret, nil
+	}
+}
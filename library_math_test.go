@@ -0,0 +1,87 @@
+package parsley
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestMathLibrary(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.LoadLibrary(MathLibrary)
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`pi()`, math.Pi, nil},
+		{`e()`, math.E, nil},
+		{`min(3, 1, 2)`, float64(1), nil},
+		{`min(price)`, 9.99, nil},
+		{`max(3, price, 2)`, 9.99, nil},
+		{`max()`, nil, errors.New("invalid number of arguments: function max expects at least 1 arguments, got 0")},
+		{`max(1, "a")`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 1 to function max: " +
+			"error parsing value as float, could not parse string 'a'")},
+		{`sqrt(16)`, float64(4), nil},
+		{`sqrt(-1)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function sqrt: [-1] is outside the domain")},
+		{`pow(2, 10)`, float64(1024), nil},
+		{`pow(10, 400)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function pow: result of [10 400] is out of range")},
+		{`pow(-8, 0.5)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function pow: [-8 0.5] is outside the domain")},
+		{`exp(0)`, float64(1), nil},
+		{`log(e())`, float64(1), nil},
+		{`2 * pi`, nil, errors.New("error evaluating expression: node evaluation failed, right side error: node evaluation failed: pi is a function, call it as pi()")},
+		{`e`, nil, errors.New("error evaluating expression: node evaluation failed: e is a function, call it as e()")},
+		{`log(0)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function log: result of [0] is out of range")},
+		{`log(-1)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function log: [-1] is outside the domain")},
+		{`log10(1000)`, float64(3), nil},
+		{`log2(8)`, float64(3), nil},
+		{`sin(0)`, float64(0), nil},
+		{`cos(0)`, float64(1), nil},
+		{`tan(0)`, float64(0), nil},
+		{`clamp(15, 0, 10)`, float64(10), nil},
+		{`clamp(-5, 0, 10)`, float64(0), nil},
+		{`clamp(5, 0, 10)`, float64(5), nil},
+		{`clamp(5, 10, 0)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function clamp: lower bound 10 is greater than upper bound 0")},
+		{`round_to(price * 1.2, 2)`, 11.99, nil},
+		{`round_to(1234.5, -2)`, float64(1200), nil},
+		{`round_to(1.5, 0.5)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 1 to function round_to: " +
+			"error parsing value as int, 0.5 is not a whole number")},
+		{`sign(-3)`, float64(-1), nil},
+		{`sign(0)`, float64(0), nil},
+		{`sign(price)`, float64(1), nil},
+		{`hypot(3, 4)`, float64(5), nil},
+		{`sqrt()`, nil, errors.New("invalid number of arguments: function sqrt expects 1 arguments, got 0")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, map[string]any{"price": 9.99})
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestMathConstantsWithoutBrackets(t *testing.T) {
+	parser, err := New(WithLibraries(MathLibrary))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	// A variable in the data with the same name as a function is still read
+	actual, err := parser.ParseAsAny(`e * pi()`, map[string]any{"e": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 2*math.Pi, actual)
+
+	_, err = parser.ParseAsAny(`e * pi()`, nil)
+	assert.ErrorIs(t, ErrNodeEvalFailed, err)
+}
+
+func TestMathLibraryDirectCall(t *testing.T) {
+	_, err := extremeFunc("min", math.Min, CoercionLenient)()
+	assert.ErrorIs(t, ErrInvalidArity, err)
+}
@@ -0,0 +1,61 @@
+package parsley
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+// RegexpLibrary provides functions for working with regular expressions, using the syntax of the regexp package.
+// Patterns written as string literals are compiled once when the expression is parsed
+var RegexpLibrary = Library{Name: "regexp", register: registerRegexp}
+
+func registerRegexp(r FunctionRegistrar) {
+	RegisterFunc2(r, "matches", func(s string, re *regexp.Regexp) (bool, error) { return re.MatchString(s), nil })
+	RegisterFunc2(r, "find", func(s string, re *regexp.Regexp) (string, error) { return re.FindString(s), nil })
+	RegisterFunc2(r, "find_all", findAll)
+	RegisterFunc3(r, "replace_regex", func(s string, re *regexp.Regexp, replacement string) (string, error) {
+		return re.ReplaceAllString(s, replacement), nil
+	})
+	registerBoundFunc3(r, "capture", func(env environment) func(string, *regexp.Regexp, any) (string, error) {
+		return func(s string, re *regexp.Regexp, group any) (string, error) {
+			return capture(s, re, group, env.semantics.Coercion)
+		}
+	})
+}
+
+func findAll(s string, re *regexp.Regexp) ([]any, error) {
+	matches := re.FindAllString(s, -1)
+	ret := make([]any, len(matches))
+	for i, m := range matches {
+		ret[i] = m
+	}
+
+	return ret, nil
+}
+
+// capture returns the given group, by number or name, from the first match. An empty string is returned when there is no match
+func capture(s string, re *regexp.Regexp, group any, coercion helpers.CoercionPolicy) (string, error) {
+	index := -1
+	if name, ok := group.(string); ok {
+		index = re.SubexpIndex(name)
+	} else {
+		i, err := toInt(group, coercion)
+		if err != nil {
+			return "", fmt.Errorf("error calling function capture: %w", err)
+		}
+		index = i
+	}
+
+	if index < 0 || index > re.NumSubexp() {
+		return "", fmt.Errorf("error calling function capture: no group %v in %s", group, re)
+	}
+
+	match := re.FindStringSubmatch(s)
+	if match == nil {
+		return "", nil
+	}
+
+	return match[index], nil
+}
@@ -0,0 +1,102 @@
+package parsley
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestMatchOperator(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	data := map[string]any{
+		"ref":     "release/v1.20",
+		"pattern": `^main$`,
+		"invalid": `(`,
+		"num":     12,
+	}
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`ref =~ "release/v\d+\.\d+"`, true, nil},
+		{`ref =~ "^release/v\d+$"`, false, nil},
+		{`ref !~ "^release/"`, false, nil},
+		{`ref !~ "^hotfix/"`, true, nil},
+		{`missing =~ "^$"`, true, nil},
+		{`num =~ "^\d+$"`, true, nil},
+		{`(ref =~ "^release/") && (num > 10)`, true, nil},
+		{`"main" =~ pattern`, true, nil},
+		{`"main" =~ invalid`, nil, errors.New("error evaluating expression: node evaluation failed: error running comparison: error in right side: " +
+			"invalid regular expression: error parsing regexp: missing closing ): `(`")},
+		{`ref =~ "("`, nil, errors.New("invalid regular expression: error parsing regexp: missing closing ): `(`")},
+		{`ref = "main"`, nil, errors.New("unexpected character: =")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, data)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestMatchOperatorCompilesLiterals(t *testing.T) {
+	node, err := parse(`ref =~ "^a+$"`, NewRegistry().load(), defaultEnvironment(), Limits{})
+	assert.Nil(t, err)
+	assert.Equal(t, `ref =~ "^a+$"`, node.String())
+
+	typ, err := node.(interface {
+		Type(Type) (Type, error)
+	}).Type(NewSchema(map[string]Type{"ref": TypeString}).Type())
+	assert.Nil(t, err)
+	assert.Equal(t, "bool", typ.String())
+}
+
+func TestRegexpLibrary(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.LoadLibrary(RegexpLibrary)
+
+	data := map[string]any{
+		"ref":     "release/v1.20",
+		"message": "fixes #12 and #34",
+		"pattern": `#(\d+)`,
+	}
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`matches(ref, "^release/v\d+\.\d+$")`, true, nil},
+		{`matches(ref, "^main$")`, false, nil},
+		{`matches(message, pattern)`, true, nil},
+		{`find(message, "#\d+")`, "#12", nil},
+		{`find(message, "z")`, "", nil},
+		{`find_all(message, "#\d+")`, []any{"#12", "#34"}, nil},
+		{`find_all(message, "z")`, []any{}, nil},
+		{`replace_regex(message, "#(\d+)", "!$1")`, "fixes !12 and !34", nil},
+		{`capture(ref, "v(\d+)\.(\d+)", 2)`, "20", nil},
+		{`capture(ref, "v(?P<major>\d+)", "major")`, "1", nil},
+		{`capture(ref, "^main/(.*)", 1)`, "", nil},
+		{`capture(ref, "v(\d+)", 2)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function capture: no group 2 in v(\\d+)")},
+		{`capture(ref, "v(\d+)", "minor")`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function capture: no group minor in v(\\d+)")},
+		{`matches(ref, "(")`, nil, errors.New("error in argument 1 to function matches: invalid regular expression: error parsing regexp: missing closing ): `(`")},
+		{`matches(ref, 12)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 1 to function matches: expected regular expression, got float64")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, data)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
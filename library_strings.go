@@ -0,0 +1,172 @@
+package parsley
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+// StringLibrary provides functions for inspecting and manipulating strings. Positions and lengths are counted in runes
+// rather than bytes
+var StringLibrary = Library{Name: "strings", register: registerStrings}
+
+func registerStrings(r FunctionRegistrar) {
+	registerLen(r)
+	RegisterFunc1(r, "lower", stringFunc(strings.ToLower))
+	RegisterFunc1(r, "upper", stringFunc(strings.ToUpper))
+	RegisterFunc1(r, "trim", stringFunc(strings.TrimSpace))
+	RegisterFunc1(r, "title", stringFunc(title))
+	RegisterFunc1(r, "reverse", stringFunc(reverse))
+	RegisterFunc2(r, "trim_prefix", stringFunc2(strings.TrimPrefix))
+	RegisterFunc2(r, "trim_suffix", stringFunc2(strings.TrimSuffix))
+	RegisterFunc2(r, "starts_with", stringFunc2(strings.HasPrefix))
+	RegisterFunc2(r, "ends_with", stringFunc2(strings.HasSuffix))
+	RegisterFunc2(r, "contains", stringFunc2(strings.Contains))
+	RegisterFunc2(r, "index_of", indexOf)
+	RegisterFunc2(r, "split", split)
+	RegisterFunc2(r, "join", func(parts []string, sep string) (string, error) { return strings.Join(parts, sep), nil })
+	RegisterFunc2(r, "repeat", repeat)
+	RegisterFunc3(r, "replace", func(s, old, replacement string) (string, error) { return strings.ReplaceAll(s, old, replacement), nil })
+
+	registerBound(r, "substring", func(env environment) Function { return substring(env.semantics.Coercion) }, Signature{
+		Params: []Param{
+			{Name: "s", Type: TypeString},
+			{Name: "start", Type: TypeNumber},
+			{Name: "end", Type: TypeNumber, Optional: true},
+		},
+		Returns: TypeString,
+	})
+
+	pad := Signature{
+		Params: []Param{
+			{Name: "s", Type: TypeString},
+			{Name: "width", Type: TypeNumber},
+			{Name: "pad", Type: TypeString, Optional: true},
+		},
+		Returns: TypeString,
+	}
+	registerBound(r, "pad_left", func(env environment) Function { return padFunc("pad_left", true, env.semantics.Coercion) }, pad)
+	registerBound(r, "pad_right", func(env environment) Function { return padFunc("pad_right", false, env.semantics.Coercion) }, pad)
+}
+
+func stringFunc[R any](fun func(string) R) func(string) (R, error) {
+	return func(s string) (R, error) {
+		return fun(s), nil
+	}
+}
+
+func stringFunc2[R any](fun func(string, string) R) func(string, string) (R, error) {
+	return func(a, b string) (R, error) {
+		return fun(a, b), nil
+	}
+}
+
+func title(s string) string {
+	runes := []rune(s)
+	start := true
+	for i, r := range runes {
+		if start && unicode.IsLetter(r) {
+			runes[i] = unicode.ToTitle(r)
+		}
+
+		start = unicode.IsSpace(r)
+	}
+
+	return string(runes)
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
+
+func indexOf(s, substr string) (float64, error) {
+	i := strings.Index(s, substr)
+	if i < 0 {
+		return -1, nil
+	}
+
+	return float64(utf8.RuneCountInString(s[:i])), nil
+}
+
+func split(s, sep string) ([]any, error) {
+	parts := strings.Split(s, sep)
+	ret := make([]any, len(parts))
+	for i, p := range parts {
+		ret[i] = p
+	}
+
+	return ret, nil
+}
+
+func repeat(s string, count int) (string, error) {
+	if count < 0 {
+		return "", fmt.Errorf("error calling function repeat: negative count %d", count)
+	}
+
+	return strings.Repeat(s, count), nil
+}
+
+func substring(coercion helpers.CoercionPolicy) Function {
+	return func(args ...any) (any, error) {
+		s, _ := coercion.ToString(args[0])
+		runes := []rune(s)
+
+		start, err := toInt(args[1], coercion)
+		if err != nil {
+			return nil, fmt.Errorf("error calling function substring: %w", err)
+		}
+
+		end := len(runes)
+		if len(args) > 2 {
+			end, err = toInt(args[2], coercion)
+			if err != nil {
+				return nil, fmt.Errorf("error calling function substring: %w", err)
+			}
+		}
+
+		if start < 0 || end > len(runes) || start > end {
+			return nil, fmt.Errorf("error calling function substring: range %d to %d out of bounds for length %d", start, end, len(runes))
+		}
+
+		return string(runes[start:end]), nil
+	}
+}
+
+func padFunc(name string, left bool, coercion helpers.CoercionPolicy) Function {
+	return func(args ...any) (any, error) {
+		s, _ := coercion.ToString(args[0])
+
+		width, err := toInt(args[1], coercion)
+		if err != nil {
+			return nil, fmt.Errorf("error calling function %s: %w", name, err)
+		}
+
+		pad := " "
+		if len(args) > 2 {
+			pad, _ = coercion.ToString(args[2])
+		}
+
+		if pad == "" {
+			return nil, fmt.Errorf("error calling function %s: pad must not be empty", name)
+		}
+
+		padding := []rune{}
+		for padRunes := []rune(pad); utf8.RuneCountInString(s)+len(padding) < width; {
+			padding = append(padding, padRunes[len(padding)%len(padRunes)])
+		}
+
+		if left {
+			return string(padding) + s, nil
+		}
+
+		return s + string(padding), nil
+	}
+}
@@ -0,0 +1,87 @@
+package parsley
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestStringLibrary(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.LoadLibrary(StringLibrary)
+
+	data := map[string]any{
+		"ref":     "refs/heads/release/v1.2",
+		"message": "  Fix the thing\n",
+		"name":    "héllo",
+		"parts":   []any{"a", "b", "c"},
+		"num":     12,
+	}
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`len(name)`, float64(5), nil},
+		{`len("")`, float64(0), nil},
+		{`len(num)`, float64(2), nil},
+		{`lower("HeLLo")`, "hello", nil},
+		{`upper(name)`, "HÉLLO", nil},
+		{`trim(message)`, "Fix the thing", nil},
+		{`title("hello big world")`, "Hello Big World", nil},
+		{`reverse(name)`, "olléh", nil},
+		{`reverse("")`, "", nil},
+		{`trim_prefix(ref, "refs/heads/")`, "release/v1.2", nil},
+		{`trim_suffix(ref, "/v1.2")`, "refs/heads/release", nil},
+		{`starts_with(ref, "refs/heads/release/")`, true, nil},
+		{`starts_with(ref, "refs/tags/")`, false, nil},
+		{`ends_with(ref, "v1.2")`, true, nil},
+		{`contains(message, "the")`, true, nil},
+		{`contains(message, "then")`, false, nil},
+		{`index_of(name, "llo")`, float64(2), nil},
+		{`index_of(name, "x")`, float64(-1), nil},
+		{`substring(name, 1)`, "éllo", nil},
+		{`substring(name, 1, 3)`, "él", nil},
+		{`substring(name, 0, 0)`, "", nil},
+		{`substring(name, 3, 2)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function substring: range 3 to 2 out of bounds for length 5")},
+		{`substring(name, 0, 6)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function substring: range 0 to 6 out of bounds for length 5")},
+		{`substring(name, 1.5)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function substring: " +
+			"error parsing value as int, 1.5 is not a whole number")},
+		{`replace(ref, "/", ":")`, "refs:heads:release:v1.2", nil},
+		{`split("a,b,c", ",")`, []any{"a", "b", "c"}, nil},
+		{`join(parts, "-")`, "a-b-c", nil},
+		{`join(split(ref, "/"), " ")`, "refs heads release v1.2", nil},
+		{`repeat("ab", 3)`, "ababab", nil},
+		{`repeat("ab", 0)`, "", nil},
+		{`repeat("ab", -1)`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function repeat: negative count -1")},
+		{`pad_left(num, 5)`, "   12", nil},
+		{`pad_left(num, 5, "0")`, "00012", nil},
+		{`pad_right(name, 8, "ab")`, "hélloaba", nil},
+		{`pad_right(name, 2)`, "héllo", nil},
+		{`pad_left(name, 8, "")`, nil, errors.New("error evaluating expression: node evaluation failed: error calling function pad_left: pad must not be empty")},
+		{`upper()`, nil, errors.New("invalid number of arguments: function upper expects 1 arguments, got 0")},
+		{`substring(name)`, nil, errors.New("invalid number of arguments: function substring expects 2 to 3 arguments, got 1")},
+		{`join(name, ",")`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function join: expected list, got string")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, data)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestStringLibraryNotLoaded(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	_, err = parser.ParseAsAny(`upper("a")`, nil)
+	assert.ErrorIs(t, ErrFunctionNotFound, err)
+}
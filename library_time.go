@@ -0,0 +1,96 @@
+package parsley
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+)
+
+// TimeLibrary provides functions for working with times and durations. Times are parsed from, and formatted as,
+// RFC3339Nano, which is RFC3339 with optional fractional seconds, unless another layout is given. Layouts may be a
+// Go reference layout or the name of one of the layouts in the time package such as "RFC1123" or "DateOnly". now()
+// and since() use the parser's clock, see Parser.SetClock
+var TimeLibrary = Library{Name: "time", register: registerTime}
+
+var namedLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC822":      time.RFC822,
+	"Kitchen":     time.Kitchen,
+	"DateOnly":    time.DateOnly,
+	"DateTime":    time.DateTime,
+	"TimeOnly":    time.TimeOnly,
+}
+
+func registerTime(r FunctionRegistrar) {
+	// now and since use the clock of the parser evaluating them
+	registerBoundFunc0(r, "now", func(env environment) func() (time.Time, error) {
+		return func() (time.Time, error) { return env.now(), nil }
+	})
+	registerBoundFunc1(r, "since", func(env environment) func(time.Time) (time.Duration, error) {
+		return func(t time.Time) (time.Duration, error) { return env.now().Sub(t), nil }
+	})
+	RegisterFunc1(r, "duration", func(d time.Duration) (time.Duration, error) { return d, nil })
+	RegisterFunc2(r, "add_duration", func(t time.Time, d time.Duration) (time.Time, error) { return t.Add(d), nil })
+	RegisterFunc1(r, "year", func(t time.Time) (float64, error) { return float64(t.Year()), nil })
+	RegisterFunc1(r, "month", func(t time.Time) (float64, error) { return float64(t.Month()), nil })
+	RegisterFunc1(r, "day", func(t time.Time) (float64, error) { return float64(t.Day()), nil })
+	RegisterFunc1(r, "hour", func(t time.Time) (float64, error) { return float64(t.Hour()), nil })
+	RegisterFunc1(r, "weekday", func(t time.Time) (string, error) { return t.Weekday().String(), nil })
+
+	registerBound(r, "parse_time", func(env environment) Function { return parseTime(env.semantics.Coercion) }, Signature{
+		Params: []Param{
+			{Name: "s", Type: TypeString},
+			{Name: "layout", Type: TypeString, Optional: true},
+		},
+		Returns: TypeTime,
+	})
+
+	registerBound(r, "format_time", func(env environment) Function { return formatTime(env.semantics.Coercion) }, Signature{
+		Params: []Param{
+			{Name: "t", Type: TypeTime},
+			{Name: "layout", Type: TypeString, Optional: true},
+		},
+		Returns: TypeString,
+	})
+}
+
+// layout returns the layout given as the optional argument at index i, defaulting to RFC3339Nano
+func layout(args []any, i int, coercion helpers.CoercionPolicy) string {
+	if len(args) <= i {
+		return time.RFC3339Nano
+	}
+
+	s, _ := coercion.ToString(args[i])
+	if named, ok := namedLayouts[s]; ok {
+		return named
+	}
+
+	return s
+}
+
+func parseTime(coercion helpers.CoercionPolicy) Function {
+	return func(args ...any) (any, error) {
+		s, _ := coercion.ToString(args[0])
+		t, err := time.Parse(layout(args, 1, coercion), s)
+		if err != nil {
+			return nil, fmt.Errorf("error calling function parse_time: %w", err)
+		}
+
+		return t, nil
+	}
+}
+
+func formatTime(coercion helpers.CoercionPolicy) Function {
+	return func(args ...any) (any, error) {
+		t, err := coercion.ToTime(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("error calling function format_time: %w", err)
+		}
+
+		return t.Format(layout(args, 1, coercion)), nil
+	}
+}
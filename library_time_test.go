@@ -0,0 +1,137 @@
+package parsley
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestTimeLibrary(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	parser.SetClock(func() time.Time { return clock })
+	parser.LoadLibrary(TimeLibrary)
+
+	data := map[string]any{
+		"build_created_at": "2024-03-01T11:30:00Z",
+		"finished_at":      time.Date(2024, 3, 1, 11, 45, 0, 0, time.UTC),
+		"timeout":          10 * time.Minute,
+		"elapsed":          15 * time.Minute,
+	}
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`now()`, clock, nil},
+		{`build_created_at > now() - duration("1h")`, true, nil},
+		{`build_created_at > now() - duration("15m")`, false, nil},
+		{`finished_at > build_created_at`, true, nil},
+		{`finished_at - build_created_at`, 15 * time.Minute, nil},
+		{`elapsed > timeout`, true, nil},
+		{`elapsed > "20m"`, false, nil},
+		{`timeout * 2 == elapsed + "5m"`, true, nil},
+		{`since(build_created_at)`, 30 * time.Minute, nil},
+		{`since(build_created_at) < duration("1h") && year(now()) == 2024`, true, nil},
+		{`add_duration(finished_at, "15m") == now()`, true, nil},
+		{`parse_time("2024-03-01")`, nil, errors.New(`error evaluating expression: node evaluation failed: error calling function parse_time: ` +
+			`parsing time "2024-03-01" as "2006-01-02T15:04:05.999999999Z07:00": cannot parse "" as "T"`)},
+		{`parse_time("2024-03-01", "DateOnly")`, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), nil},
+		{`parse_time("01/03/2024", "02/01/2006") < now()`, true, nil},
+		{`format_time(now())`, "2024-03-01T12:00:00Z", nil},
+		{`format_time(build_created_at, "Kitchen")`, "11:30AM", nil},
+		{`format_time(now(), "Monday 2 Jan")`, "Friday 1 Mar", nil},
+		{`year(build_created_at)`, float64(2024), nil},
+		{`month(build_created_at)`, float64(3), nil},
+		{`day(build_created_at)`, float64(1), nil},
+		{`hour(build_created_at)`, float64(11), nil},
+		{`weekday(build_created_at)`, "Friday", nil},
+		{`duration("soon")`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function duration: " +
+			"error parsing value as duration, could not parse string 'soon'")},
+		{`year("yesterday")`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function year: " +
+			"error parsing value as time, could not parse string 'yesterday'")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, data)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestTimeSchema(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.LoadLibrary(TimeLibrary)
+
+	schema, err := SchemaFor(struct {
+		CreatedAt time.Time     `json:"created_at"`
+		Timeout   time.Duration `json:"timeout"`
+		Name      string        `json:"name"`
+	}{})
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		input  string
+		result string
+		err    error
+	}{
+		{`created_at > now() - duration("1h")`, "bool", nil},
+		{`created_at - now()`, "duration", nil},
+		{`since(created_at) > timeout`, "bool", nil},
+		{`created_at + created_at`, "any", errors.New("type check failed: unsupported op for time and time: +")},
+		{`year(name)`, "number", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.Check(tc.input, schema)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual.String())
+		})
+	}
+}
+
+func TestTimeLibraryClock(t *testing.T) {
+	clock := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	base := NewRegistry()
+	base.LoadLibrary(TimeLibrary)
+	base.LoadModule("t", TimeLibrary)
+
+	parser, err := New(WithRegistry(base), WithCache(), WithClock(func() time.Time { return clock }))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	testCases := []struct {
+		input  string
+		result any
+	}{
+		{`now()`, clock},
+		{`t.now()`, clock},
+		{`since(now())`, time.Duration(0)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+
+	// The clock is read when the expression is evaluated, so cached expressions see a new clock
+	later := clock.Add(time.Hour)
+	parser.SetClock(func() time.Time { return later })
+
+	actual, err := parser.ParseAsAny(`now()`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, later, actual)
+}
@@ -0,0 +1,98 @@
+package parsley
+
+import (
+	"slices"
+	"strings"
+)
+
+// moduleImport makes the functions in a module callable by a shorter name
+type moduleImport struct {
+	module string
+	alias  string
+}
+
+// moduleRegistrar registers functions in to a module
+type moduleRegistrar struct {
+	r      FunctionRegistrar
+	module string
+}
+
+func (m moduleRegistrar) RegisterFunction(name string, fun Function, sig ...Signature) {
+	m.r.RegisterFunction(m.module+"."+name, fun, sig...)
+}
+
+func (m moduleRegistrar) registerBound(name string, bind func(env environment) Function, sig ...Signature) {
+	registerBound(m.r, m.module+"."+name, bind, sig...)
+}
+
+// RegisterModule registers functions which are called with the module name as a prefix, so that the upper function in
+// the str module is called as str.upper(x). Functions with the same name as one already in the module will replace it
+func (r *Registry) RegisterModule(module string, funs map[string]Function) {
+	r.update(func(s *snapshot) {
+		for name, fun := range funs {
+			full := module + "." + name
+			s.functions[full] = fun
+			delete(s.binders, full)
+			delete(s.signatures, full)
+			delete(s.docs, full)
+		}
+	})
+}
+
+// RegisterModule registers functions in a module, see Registry.RegisterModule
+func (p *Parser) RegisterModule(module string, funs map[string]Function) {
+	p.Registry.RegisterModule(module, funs)
+}
+
+// LoadModule registers every function in the given libraries in a module, see Parser.LoadModule
+func (r *Registry) LoadModule(module string, libs ...Library) {
+	for _, lib := range libs {
+		lib.register(moduleRegistrar{r, module})
+	}
+}
+
+// LoadModule registers every function in the given libraries in a module, so that LoadModule("str", StringLibrary)
+// makes upper callable as str.upper(x) without colliding with other functions
+func (p *Parser) LoadModule(module string, libs ...Library) {
+	p.Registry.LoadModule(module, libs...)
+}
+
+// Import makes the functions in a module callable as alias.name, or by name alone if the alias is empty. Functions
+// registered without a module take precedence, then the most recent import. The module doesn't need to be registered
+// yet
+func (r *Registry) Import(module, alias string) {
+	r.update(func(s *snapshot) {
+		s.imports = slices.DeleteFunc(s.imports, func(i moduleImport) bool { return i.alias == alias && alias != "" })
+		s.imports = append(s.imports, moduleImport{module: module, alias: alias})
+	})
+}
+
+// Import makes the functions in a module callable by a shorter name, see Registry.Import
+func (p *Parser) Import(module, alias string) {
+	p.Registry.Import(module, alias)
+}
+
+// resolve returns the registered name of the function called by the given name
+func (s *snapshot) resolve(name string) string {
+	if _, ok := s.functions[name]; ok {
+		return name
+	}
+
+	for _, imp := range slices.Backward(s.imports) {
+		full := imp.module + "." + name
+		if imp.alias != "" {
+			rest, ok := strings.CutPrefix(name, imp.alias+".")
+			if !ok {
+				continue
+			}
+
+			full = imp.module + "." + rest
+		}
+
+		if _, ok := s.functions[full]; ok {
+			return full
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,86 @@
+package parsley
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestModules(t *testing.T) {
+	parser, err := New(WithCache())
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.LoadModule("str", StringLibrary)
+	parser.RegisterModule("tenant", map[string]Function{
+		"name":  func(_ ...any) (any, error) { return "acme", nil },
+		"upper": func(_ ...any) (any, error) { return "tenant upper", nil },
+	})
+	parser.RegisterFunction("upper", func(_ ...any) (any, error) { return "user upper", nil })
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`str.upper("a")`, "A", nil},
+		{`upper("a")`, "user upper", nil},
+		{`tenant.name()`, "acme", nil},
+		{`tenant.upper("a")`, "tenant upper", nil},
+		{`str.missing("a")`, nil, errors.New("function not found: str.missing")},
+		{`lower("A")`, nil, errors.New("function not found: lower")},
+		{`str.upper(1, 2)`, nil, errors.New("invalid number of arguments: function str.upper expects 1 arguments, got 2")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, nil)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	parser, err := New(WithCache())
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.LoadModule("str", StringLibrary)
+	parser.RegisterModule("tenant", map[string]Function{
+		"upper": func(_ ...any) (any, error) { return "tenant upper", nil },
+	})
+
+	// Parsed before the imports, so the cached expressions must be parsed again
+	_, err = parser.ParseAsAny(`lower("A")`, nil)
+	assert.ErrorEqual(t, errors.New("function not found: lower"), err)
+
+	parser.Import("str", "")
+	parser.Import("tenant", "")
+	parser.Import("str", "s")
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`lower("A")`, "a", nil},
+		{`upper("a")`, "tenant upper", nil},
+		{`s.upper("a")`, "A", nil},
+		{`str.upper("a")`, "A", nil},
+		{`t.upper("a")`, nil, errors.New("function not found: t.upper")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, nil)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+
+	// Functions without a module take precedence over imports
+	parser.RegisterFunction("lower", func(_ ...any) (any, error) { return "user lower", nil })
+	actual, err := parser.ParseAsAny(`lower("A")`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "user lower", actual)
+}
@@ -0,0 +1,66 @@
+package parsley
+
+import (
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestNullSemantics(t *testing.T) {
+	parser, err := NewParser(true)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.EnableNullSemantics()
+
+	data := map[string]any{"branch": "main", "score": 7, "reviewer": nil}
+
+	testCases := []struct {
+		input   string
+		result  bool
+		unknown bool
+	}{
+		{`branch == "main"`, true, false},
+		{`reviewer == ""`, false, true},
+		{`missing > 3`, false, true},
+		{`missing + 1 > 3`, false, true},
+		{`-missing < 0`, false, true},
+		{`branch == "main" && missing > 3`, false, true},
+		{`branch == "dev" && missing > 3`, false, false},
+		{`branch == "main" || missing > 3`, true, false},
+		{`is_null(missing)`, true, false},
+		{`is_null(score)`, false, false},
+		{`is_null(missing > 3) && score > 5`, true, false},
+		{`not(missing)`, false, true},
+		{`not(missing > 3)`, false, true},
+		{`not(score > 5)`, false, false},
+		{`is_null(not(reviewer))`, true, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsBool(tc.input, data)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.result, actual)
+
+			value, err := parser.ParseAsAny(tc.input, data)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.unknown, IsNull(value))
+		})
+	}
+}
+
+func TestNullsWithoutSemantics(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	actual, err := parser.ParseAsBool(`reviewer == ""`, map[string]any{"reviewer": nil})
+	assert.Nil(t, err)
+	assert.Equal(t, true, actual)
+
+	_, err = parser.ParseAsBool(`missing > 3`, nil)
+	assert.ErrorIs(t, ErrComparisonFailed, err)
+
+	_, err = parser.ParseAsBool(`not(missing)`, nil)
+	assert.ErrorIs(t, ErrInvalidArgument, err)
+}
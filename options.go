@@ -0,0 +1,211 @@
+package parsley
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/cache"
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/nodes"
+)
+
+// ErrInvalidOption is returned when an option is given an invalid value
+const ErrInvalidOption = helpers.ConstError("invalid option")
+
+// Option configures a parser created by New
+type Option func(m *Parser) error
+
+// Limits restricts the expressions a parser will accept, which is useful when expressions come from users. Zero
+// values are unlimited
+type Limits struct {
+	// MaxLength is the maximum number of characters in an expression
+	MaxLength int
+	// MaxDepth is the maximum nesting of operators, parentheses and function calls in an expression
+	MaxDepth int
+}
+
+// New creates a parser configured by the given options. By default expressions aren't cached, numbers are float64,
+// conversions are lenient and only the built-in functions are available
+func New(opts ...Option) (*Parser, error) {
+	m := &Parser{
+		cache:     cache.NewNoOpCache(),
+		clock:     time.Now,
+		semantics: &nodes.Semantics{},
+		logger:    slog.New(slog.DiscardHandler),
+		stats:     newStats(),
+		Registry:  NewRegistry(),
+	}
+	m.unregistered = m.Registry.load().version
+
+	for _, opt := range opts {
+		err := opt(m)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// WithCache caches up to 10,000 parsed expressions, so that evaluating the same expression again skips parsing. The
+// cache is backed by github.com/dgraph-io/ristretto/v2, use WithCacheSize for more
+func WithCache() Option {
+	return WithCacheSize(cache.DefaultSize)
+}
+
+// WithCacheSize caches up to size parsed expressions, see WithCache
+func WithCacheSize(size int64) Option {
+	return func(m *Parser) error {
+		if size <= 0 {
+			return fmt.Errorf("%w: cache size must be positive, got %d", ErrInvalidOption, size)
+		}
+
+		store, err := cache.NewCache(size)
+		if err != nil {
+			return err //nolint:wrapcheck // Error is already wrapped
+		}
+
+		return WithCacheStore(store)(m)
+	}
+}
+
+// WithLRUCache caches up to size parsed expressions in a least recently used cache, see NewLRUCache
+func WithLRUCache(size int) Option {
+	return func(m *Parser) error {
+		if size <= 0 {
+			return fmt.Errorf("%w: cache size must be positive, got %d", ErrInvalidOption, size)
+		}
+
+		return WithCacheStore(cache.NewLRU(size))(m)
+	}
+}
+
+// WithCacheStore caches parsed expressions in the given store. The store is closed when the parser is closed. Parsers
+// with their own registries, including those given by WithRegistry, can share a store without seeing each other's
+// expressions
+func WithCacheStore(store Cache) Option {
+	return func(m *Parser) error {
+		if store == nil {
+			return fmt.Errorf("%w: cache can't be nil", ErrInvalidOption)
+		}
+
+		m.cache.Close()
+		m.cache = store
+
+		return nil
+	}
+}
+
+// WithStrict checks every expression against the schema when it is parsed, so that type errors such as comparing a
+// string with a number are returned before the expression is evaluated. A nil schema allows any variable, but still
+// checks operators and function calls
+func WithStrict(schema *Schema) Option {
+	return func(m *Parser) error {
+		m.strict = true
+		m.schema = schema
+
+		return nil
+	}
+}
+
+// WithCoercion sets the conversions made between types, see Parser.SetCoercionPolicy
+func WithCoercion(policy CoercionPolicy) Option {
+	return func(m *Parser) error {
+		m.SetCoercionPolicy(policy)
+		return nil
+	}
+}
+
+// WithDecimal switches numbers to exact decimals, see Parser.EnableDecimal
+func WithDecimal(ctx DecimalContext) Option {
+	return func(m *Parser) error {
+		m.EnableDecimal(ctx)
+		return nil
+	}
+}
+
+// WithIntegers keeps integers as int64, see Parser.EnableIntegers
+func WithIntegers() Option {
+	return func(m *Parser) error {
+		m.EnableIntegers()
+		return nil
+	}
+}
+
+// WithNullSemantics treats nil as an unknown value, see Parser.EnableNullSemantics
+func WithNullSemantics() Option {
+	return func(m *Parser) error {
+		m.EnableNullSemantics()
+		return nil
+	}
+}
+
+// WithLimits restricts the size of the expressions the parser will accept
+func WithLimits(limits Limits) Option {
+	return func(m *Parser) error {
+		if limits.MaxLength < 0 || limits.MaxDepth < 0 {
+			return fmt.Errorf("%w: limits can't be negative", ErrInvalidOption)
+		}
+
+		m.limits = limits
+
+		return nil
+	}
+}
+
+// WithLibraries loads the given libraries of functions, see Parser.LoadLibrary
+func WithLibraries(libs ...Library) Option {
+	return func(m *Parser) error {
+		m.LoadLibrary(libs...)
+		return nil
+	}
+}
+
+// WithRegistry starts the parser with a copy of the registry, rather than only the built-in functions. Functions
+// registered with the parser afterwards don't change the registry, and later changes to the registry aren't seen by
+// the parser, so one registry can be the shared base for many parsers. It must be given before options which register
+// functions, such as WithLibraries, as their functions would be discarded, otherwise ErrInvalidOption is returned
+func WithRegistry(base *Registry) Option {
+	return func(m *Parser) error {
+		if base == nil {
+			return fmt.Errorf("%w: registry can't be nil", ErrInvalidOption)
+		}
+
+		if m.Registry.load().version != m.unregistered {
+			return fmt.Errorf("%w: registry must be given before options which register functions", ErrInvalidOption)
+		}
+
+		m.Registry = base.Clone()
+		m.unregistered = m.Registry.load().version
+
+		return nil
+	}
+}
+
+// WithClock replaces the clock used by time functions, see Parser.SetClock
+func WithClock(clock func() time.Time) Option {
+	return func(m *Parser) error {
+		if clock == nil {
+			return fmt.Errorf("%w: clock can't be nil", ErrInvalidOption)
+		}
+
+		m.SetClock(clock)
+
+		return nil
+	}
+}
+
+// WithLogger logs expressions as they are parsed, and expressions which fail to parse or evaluate, at debug level
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *Parser) error {
+		if logger == nil {
+			return fmt.Errorf("%w: logger can't be nil", ErrInvalidOption)
+		}
+
+		m.logger = logger
+
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package parsley
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestNew(t *testing.T) {
+	clock := func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	parser, err := New(
+		WithCacheSize(100),
+		WithCoercion(CoercionStrict),
+		WithIntegers(),
+		WithLibraries(StringLibrary, TimeLibrary),
+		WithClock(clock),
+	)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	actual, err := parser.ParseAsAny(`upper(weekday(now())) + ":" + format_time(now(), "DateOnly")`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "TUESDAY:2024-01-02", actual)
+
+	actual, err = parser.ParseAsAny(`id + 1`, map[string]any{"id": int64(9007199254740993)})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740994), actual)
+
+	_, err = parser.ParseAsBool(`"yes"`, nil)
+	assert.ErrorEqual(t, errors.New("error parsing value as bool, invalid type: string"), err)
+}
+
+func TestNewErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		opt  Option
+		err  error
+	}{
+		{"cache size", WithCacheSize(0), errors.New("invalid option: cache size must be positive, got 0")},
+		{"limits", WithLimits(Limits{MaxDepth: -1}), errors.New("invalid option: limits can't be negative")},
+		{"clock", WithClock(nil), errors.New("invalid option: clock can't be nil")},
+		{"logger", WithLogger(nil), errors.New("invalid option: logger can't be nil")},
+		{"registry", WithRegistry(nil), errors.New("invalid option: registry can't be nil")},
+		{"metrics hook", WithMetricsHook(nil), errors.New("invalid option: metrics hook can't be nil")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser, err := New(tc.opt)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.ErrorIs(t, ErrInvalidOption, err)
+			assert.Equal(t, true, parser == nil)
+		})
+	}
+}
+
+func TestWithLimits(t *testing.T) {
+	parser, err := New(WithLimits(Limits{MaxLength: 20, MaxDepth: 3}))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	testCases := []struct {
+		input string
+		err   error
+	}{
+		{`1 + 2 * 3 == 7`, nil},
+		{`((1))`, nil},
+		{`1 + 2 + 3 + 4 + 5 + 6`, errors.New("expression exceeds limit: expression is 21 characters long, the limit is 20")},
+		{`(((1)))`, errors.New("expression exceeds limit: expression is nested more than 3 deep")},
+		{`---1`, errors.New("expression exceeds limit: expression is nested more than 3 deep")},
+		{`not(not(not(1)))`, errors.New("expression exceeds limit: expression is nested more than 3 deep")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := parser.ParseAsAny(tc.input, nil)
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err != nil {
+				assert.ErrorIs(t, ErrLimitExceeded, err)
+			}
+		})
+	}
+}
+
+func TestWithStrict(t *testing.T) {
+	schema := NewSchema(map[string]Type{"name": TypeString})
+
+	parser, err := New(WithCache(), WithStrict(schema))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	actual, err := parser.ParseAsBool(`name == "main"`, map[string]any{"name": "main"})
+	assert.Nil(t, err)
+	assert.Equal(t, true, actual)
+
+	_, err = parser.ParseAsBool(`name > 3`, map[string]any{"name": "main"})
+	assert.ErrorIs(t, ErrTypeCheckFailed, err)
+
+	_, err = parser.ParseAsBool(`nmae == "main"`, map[string]any{"name": "main"})
+	assert.ErrorIs(t, ErrTypeCheckFailed, err)
+}
+
+func TestWithLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	parser, err := New(WithLogger(logger))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	_, err = parser.ParseAsAny(`1 + 2`, nil)
+	assert.Nil(t, err)
+
+	_, err = parser.ParseAsAny(`1 +`, nil)
+	assert.Equal(t, true, err != nil)
+
+	_, err = parser.ParseAsAny(`missing > 1`, nil)
+	assert.Equal(t, true, err != nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, true, strings.Contains(lines[0], `msg="parsed expression" expression="1 + 2"`))
+	assert.Equal(t, true, strings.Contains(lines[1], `msg="expression failed to parse" expression="1 +"`))
+	assert.Equal(t, true, strings.Contains(lines[3], `msg="expression failed to evaluate" expression="missing > 1"`))
+}
@@ -4,30 +4,52 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/scottkgregory/parsley/internal/decimal"
 	"github.com/scottkgregory/parsley/internal/helpers"
 	"github.com/scottkgregory/parsley/internal/nodes"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
-// ErrFunctionNotFound is returned when an unrecognised function is found
-const ErrFunctionNotFound = helpers.ConstError("function not found")
+const (
+	// ErrFunctionNotFound is returned when an unrecognised function is found
+	ErrFunctionNotFound = helpers.ConstError("function not found")
+
+	// ErrLimitExceeded is returned when an expression is larger than the parser's limits allow
+	ErrLimitExceeded = helpers.ConstError("expression exceeds limit")
+)
 
 type parser struct {
 	tokenizer *tokenizer
-	reg       *registry
+	reg       *snapshot
+	env       environment
+	semantics *nodes.Semantics
+	limits    Limits
+	depth     int
+
+	// argument is the position of the tokenizer at the start of the current function argument, lambdas may only
+	// appear there
+	argument int
 }
 
-func parse(str string, reg *registry) (nodes.Node, error) {
+func parse(str string, reg *snapshot, env environment, limits Limits) (nodes.Node, error) {
+	if length := utf8.RuneCountInString(str); limits.MaxLength > 0 && length > limits.MaxLength {
+		return nil, fmt.Errorf("%w: expression is %d characters long, the limit is %d", ErrLimitExceeded, length, limits.MaxLength)
+	}
+
 	t, err := newTokenizer(str, reg)
 	if err != nil {
 		return nil, err
 	}
 
-	return (&parser{t, reg}).parseExpression()
+	return (&parser{tokenizer: t, reg: reg, env: env, semantics: env.semantics, limits: limits, argument: -1}).parseExpression()
 }
 
 func (p *parser) parseExpression() (nodes.Node, error) {
-	expr, err := p.parseAddSubtract()
+	expr, err := p.parseBinary(precedenceLowest)
 	if err != nil {
 		return nil, err
 	}
@@ -40,30 +62,88 @@ func (p *parser) parseExpression() (nodes.Node, error) {
 	return expr, nil
 }
 
-func (p *parser) parseAddSubtract() (nodes.Node, error) {
+// Binary operator precedence, higher values bind more tightly. The levels are spaced apart so that registered
+// operators can bind between them
+const (
+	precedenceLowest = 0
+	// PrecedenceOr is the precedence of ||
+	PrecedenceOr = 10
+	// PrecedenceAnd is the precedence of &&
+	PrecedenceAnd = 20
+	// PrecedenceComparison is the precedence of comparisons such as == and in
+	PrecedenceComparison = 30
+	// PrecedenceAdditive is the precedence of + and -, and the default for registered operators
+	PrecedenceAdditive = 40
+	// PrecedenceMultiplicative is the precedence of * / % and ^
+	PrecedenceMultiplicative = 50
+)
+
+// Associativity decides how a chain of operators with the same precedence is grouped
+type Associativity int
+
+const (
+	// LeftAssociative groups a - b - c as (a - b) - c
+	LeftAssociative Associativity = iota
+	// RightAssociative groups a ** b ** c as a ** (b ** c)
+	RightAssociative
+)
+
+// Precedence is how tightly a registered binary operator binds, see Parser.RegisterBinaryNode
+type Precedence struct {
+	// Level is one of the Precedence constants, or a value between them
+	Level int
+	// Associativity is how a chain of operators with the same level is grouped
+	Associativity Associativity
+}
+
+// defaultPrecedence is used for operators registered without a precedence, which bind like +
+var defaultPrecedence = Precedence{Level: PrecedenceAdditive}
+
+var builtinPrecedence = map[string]int{
+	"||": PrecedenceOr,
+	"&&": PrecedenceAnd,
+	"==": PrecedenceComparison,
+	"<":  PrecedenceComparison,
+	">":  PrecedenceComparison,
+	"=~": PrecedenceComparison,
+	"!~": PrecedenceComparison,
+	"in": PrecedenceComparison,
+	"+":  PrecedenceAdditive,
+	"-":  PrecedenceAdditive,
+	"*":  PrecedenceMultiplicative,
+	"/":  PrecedenceMultiplicative,
+	"%":  PrecedenceMultiplicative,
+	"^":  PrecedenceMultiplicative,
+}
+
+// precedence returns the precedence of the token if it is a binary operator, or the first keyword of a node with
+// several operands
+func (p *parser) precedence(token string) (Precedence, bool) {
+	if _, ok := p.reg.binaryNodes[token]; ok {
+		return p.reg.precedence[token], true
+	}
+
+	if n, ok := p.reg.nary[token]; ok {
+		return n.precedence, true
+	}
+
+	level, ok := builtinPrecedence[token]
+	return Precedence{Level: level}, ok
+}
+
+// parseBinary parses a chain of binary operators, consuming only those which bind at least as tightly as minPrecedence
+func (p *parser) parseBinary(minPrecedence int) (nodes.Node, error) {
 	// Parse the left hand side
-	left, err := p.parseMultiplyDivide()
+	left, err := p.parseUnary()
 	if err != nil {
 		return nil, err
 	}
 
 	for {
-		// Work out the operator
-		var op string
-		switch p.tokenizer.Token {
-		case "+", "-":
-			op = p.tokenizer.Token
-		}
-
-		var mapKey string
-		for k := range maps.Keys(p.reg.binaryNodes) {
-			if k == p.tokenizer.Token {
-				mapKey = k
-			}
-		}
-
 		// Binary operator found?
-		if op == "" && mapKey == "" {
+		op := p.tokenizer.Token
+		prec, ok := p.precedence(op)
+		if !ok || prec.Level < minPrecedence {
 			return left, nil
 		}
 
@@ -73,58 +153,74 @@ func (p *parser) parseAddSubtract() (nodes.Node, error) {
 			return nil, err
 		}
 
-		// Parse the right hand side of the expression
-		right, err := p.parseMultiplyDivide()
+		if n, ok := p.reg.nary[op]; ok {
+			left, err = p.naryNode(n, left)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		// Parse the right hand side of the expression. For left associative operators only those which bind more
+		// tightly are consumed, right associative operators also consume those at the same level
+		next := prec.Level + 1
+		if prec.Associativity == RightAssociative {
+			next = prec.Level
+		}
+
+		right, err := p.parseBinary(next)
 		if err != nil {
 			return nil, err
 		}
 
 		// Create a binary node and use it as the left-hand side from now on
-		if n, ok := p.reg.binaryNodes[mapKey]; ok {
-			left = n(left, right)
-		} else {
-			left = nodes.NewBinaryNode(left, right, op)
+		left, err = p.binaryNode(op, left, right)
+		if err != nil {
+			return nil, err
 		}
 	}
 }
 
-func (p *parser) parseMultiplyDivide() (nodes.Node, error) {
-	// Parse the left hand side
-	left, err := p.parseUnary()
-	if err != nil {
-		return nil, err
+func (p *parser) binaryNode(op string, left, right nodes.Node) (nodes.Node, error) {
+	if n, ok := p.reg.binaryNodes[op]; ok {
+		return construct(op, func() nodes.Node { return n(left, right) })
 	}
 
-	for {
-		// Work out the operator
-		var op string
-		switch p.tokenizer.Token {
-		case "*", "/", "^", "==", "=", ">", "<", "&&", "&", "||", "|":
-			op = p.tokenizer.Token
-		}
-
-		// Binary operator found?
-		if op == "" {
-			return left, nil
-		}
-
-		// Skip the operator
-		err = p.tokenizer.NextToken()
+	// Literal patterns are compiled once, here, rather than on every evaluation
+	if op == "=~" || op == "!~" {
+		var err error
+		right, err = compileLiteral(right)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		// Parse the right hand side of the expression
-		var right, err = p.parseUnary()
-		if err != nil {
-			return nil, err
-		}
+	node := nodes.NewBinaryNode(left, right, op)
+	node.Semantics = p.semantics
+
+	return node, nil
+}
 
-		left = nodes.NewBinaryNode(left, right, op)
+// compileLiteral compiles the node in to a regular expression if it is a string literal
+func compileLiteral(n nodes.Node) (nodes.Node, error) {
+	s, ok := n.(*nodes.StringNode)
+	if !ok {
+		return n, nil
 	}
+
+	return nodes.NewRegexpNode(s.StringValue) //nolint:wrapcheck // Error is already wrapped
 }
 
 func (p *parser) parseUnary() (nodes.Node, error) {
+	// Every operand, including those nested in parentheses and function calls, is parsed from here
+	p.depth++
+	defer func() { p.depth-- }()
+
+	if p.limits.MaxDepth > 0 && p.depth > p.limits.MaxDepth {
+		return nil, fmt.Errorf("%w: expression is nested more than %d deep", ErrLimitExceeded, p.limits.MaxDepth)
+	}
+
 	// Positive operator is a no-op so just skip it
 	if p.tokenizer.Token == "+" {
 		// Skip
@@ -157,22 +253,65 @@ func (p *parser) parseUnary() (nodes.Node, error) {
 		}
 
 		if n, ok := p.reg.unaryNodes[mapKey]; ok {
-			return n(right), nil
+			return construct(mapKey, func() nodes.Node { return n(right) })
 		}
 
 		// Create unary node
-		return nodes.NewUnaryNode(right, "-"), nil
+		node := nodes.NewUnaryNode(right, "-")
+		node.Semantics = p.semantics
+
+		return node, nil
+	}
+
+	// No positive/negative operator so parse a leaf node, followed by any postfix operators
+	leaf, err := p.parseLeaf()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parsePostfix(leaf)
+}
+
+// construct calls a registered node constructor, wrapping the node so that panics in user code are returned as errors
+func construct(name string, fun func() nodes.Node) (node nodes.Node, err error) {
+	defer nodes.Recover(name, &err)
+
+	return nodes.NewRecoverNode(name, fun()), nil
+}
+
+// number returns the value of the current number token, as a decimal or integer when those semantics are enabled
+func (p *parser) number() (any, error) {
+	text := p.tokenizer.NumberText
+	switch {
+	case p.semantics.Decimal != nil:
+		return decimal.Parse(text) //nolint:wrapcheck // Error is already wrapped
+	case p.semantics.Integers && !strings.Contains(text, "."):
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", nodes.ErrIntegerOverflow, text)
+		}
+
+		return i, nil
 	}
 
-	// No positive/negative operator so parse a leaf node
-	return p.parseLeaf()
+	return p.tokenizer.Number, nil
 }
 
 func (p *parser) parseLeaf() (nodes.Node, error) {
+	// Registered grammar?
+	if hook, ok := p.reg.hooks[p.tokenizer.Token]; ok {
+		return p.parseHook(p.tokenizer.Token, hook)
+	}
+
 	// Is it a number?
 	if p.tokenizer.Token == number {
-		node := nodes.NewNumberNode(p.tokenizer.Number)
-		err := p.tokenizer.NextToken()
+		value, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+
+		node := nodes.NewNumberNode(value)
+		err = p.tokenizer.NextToken()
 		if err != nil {
 			return nil, err
 		}
@@ -188,7 +327,7 @@ func (p *parser) parseLeaf() (nodes.Node, error) {
 		}
 
 		// Parse a top-level expression
-		node, err := p.parseAddSubtract()
+		node, err := p.parseBinary(precedenceLowest)
 		if err != nil {
 			return nil, err
 		}
@@ -207,44 +346,49 @@ func (p *parser) parseLeaf() (nodes.Node, error) {
 		return node, nil
 	}
 
-	// Quotes?
-	// TODO: This does not allow for escaping quotes
-	if p.tokenizer.Token == `"` {
-		// Skip '"'
+	// String?
+	if p.tokenizer.Token == str {
+		node := nodes.NewStringNode(p.tokenizer.String)
 		err := p.tokenizer.NextToken()
 		if err != nil {
 			return nil, err
 		}
-
-		s := ""
-		for {
-			err := p.tokenizer.NextToken()
-			if err != nil {
-				return nil, err
-			}
-
-			s += p.tokenizer.Identifier
-
-			// Check and skip '"'
-			if p.tokenizer.Token == `"` {
-				err := p.tokenizer.NextToken()
-				if err != nil {
-					return nil, err
-				}
-				return nodes.NewStringNode(s), nil
-			}
-		}
+		return node, nil
 	}
 
 	// Variable
 	if p.tokenizer.Token == identifier {
 		// Capture the name and skip it
 		name := p.tokenizer.Identifier
+		argument := p.tokenizer.position == p.argument
 		err := p.tokenizer.NextToken()
 		if err != nil {
 			return nil, err
 		}
 
+		// An arrow introduces a lambda, the body is everything up to the end of the argument
+		if p.tokenizer.Token == "=>" {
+			if !argument {
+				return nil, fmt.Errorf("lambda %s => ... can only be passed as a function argument", name)
+			}
+
+			if strings.Contains(name, ".") {
+				return nil, fmt.Errorf("invalid lambda parameter %s, parameter names can't contain '.'", name)
+			}
+
+			err := p.tokenizer.NextToken()
+			if err != nil {
+				return nil, err
+			}
+
+			body, err := p.parseBinary(precedenceLowest)
+			if err != nil {
+				return nil, err
+			}
+
+			return nodes.NewLambdaNode(name, body), nil
+		}
+
 		// Parens indicate a function call, otherwise just a variable
 		if p.tokenizer.Token == "(" {
 			// Function call
@@ -257,9 +401,10 @@ func (p *parser) parseLeaf() (nodes.Node, error) {
 
 			// Parse arguments
 			var arguments = []nodes.Node{}
-			for {
+			for p.tokenizer.Token != ")" {
 				// Parse argument and add to list
-				n, err := p.parseAddSubtract()
+				p.argument = p.tokenizer.position
+				n, err := p.parseBinary(precedenceLowest)
 				if err != nil {
 					return nil, err
 				}
@@ -289,16 +434,47 @@ func (p *parser) parseLeaf() (nodes.Node, error) {
 				return nil, err
 			}
 
-			fun, ok := p.reg.functions[name]
+			// Functions in imported modules can be called by a shorter name
+			full := p.reg.resolve(name)
+			fun, ok := p.reg.functions[full]
 			if !ok {
 				return nil, fmt.Errorf("%w: %s", ErrFunctionNotFound, name)
 			}
 
+			// Some functions depend on settings of the parser, such as the clock
+			if bind, ok := p.reg.binders[full]; ok {
+				fun = bind(p.env)
+			}
+
+			sig := p.reg.signature(full)
+			if sig != nil {
+				err = sig.CheckArity(name, len(arguments))
+				if err != nil {
+					return nil, err //nolint:wrapcheck // Error is already wrapped
+				}
+
+				for i, arg := range arguments {
+					if sig.Param(i).Type.Is(types.KindRegexp) {
+						arguments[i], err = compileLiteral(arg)
+						if err != nil {
+							return nil, fmt.Errorf("error in argument %d to function %s: %w", i, name, err)
+						}
+					}
+				}
+			}
+
 			// Create the function call node
-			return nodes.NewFunctionNode(fun, name, arguments...), nil
+			node := nodes.NewFunctionNode(fun, name, arguments...)
+			node.Signature = sig
+			node.Semantics = p.semantics
+
+			return node, nil
 		}
 
-		return nodes.NewVariableNode(name), nil
+		node := nodes.NewVariableNode(name)
+		_, node.Function = p.reg.functions[p.reg.resolve(name)]
+
+		return node, nil
 	}
 
 	// Don't Understand
@@ -3,6 +3,8 @@ package parsley
 
 import (
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/scottkgregory/parsley/internal/cache"
 	"github.com/scottkgregory/parsley/internal/helpers"
@@ -11,25 +13,57 @@ import (
 
 // Parser provides parsing and evaluation functionality
 type Parser struct {
-	cache    cache.Store[string, nodes.Node]
-	Registry *registry
+	cache     cache.Store[string, nodes.Node]
+	clock     func() time.Time
+	semantics *nodes.Semantics
+	limits    Limits
+	strict    bool
+	schema    *Schema
+	logger    *slog.Logger
+	stats     *stats
+	Registry  *Registry
+
+	// unregistered is the version of the registry before options registered anything, WithRegistry can only replace
+	// a registry which is still at this version
+	unregistered uint64
 }
 
-// NewParser configures a new parser. If a cache it required one will be set up using github.com/dgraph-io/ristretto/v2
-func NewParser(withCache bool) (m *Parser, err error) {
-	m = &Parser{
-		Registry: newRegistry(),
-	}
+// NewParser configures a new parser. If a cache it required one will be set up using github.com/dgraph-io/ristretto/v2.
+// Use New for more options
+func NewParser(withCache bool) (*Parser, error) {
 	if withCache {
-		m.cache, err = cache.NewCache()
-		if err != nil {
-			return nil, err //nolint:wrapcheck // Error is already wrapped
-		}
-	} else {
-		m.cache = cache.NewNoOpCache()
+		return New(WithCache())
 	}
 
-	return m, nil
+	return New()
+}
+
+// SetClock replaces the clock used by time functions such as now(), which is useful for testing
+func (m *Parser) SetClock(clock func() time.Time) {
+	m.clock = clock
+}
+
+// EnableIntegers keeps whole number literals, and arithmetic on two integers, as int64 rather than float64. This keeps
+// 64-bit IDs exact, where float64 loses precision above 2^53. + - * % and whole powers stay as integers and return
+// ErrIntegerOverflow rather than wrapping, / stays an integer only when the division is exact, and dividing by zero
+// returns ErrDivisionByZero. Anything involving a float is calculated as before. Call this before parsing any
+// expressions, as cached expressions keep the literals they were parsed with
+func (m *Parser) EnableIntegers() {
+	m.semantics.Integers = true
+}
+
+// EnableNullSemantics treats nil as an unknown value, as SQL does. Arithmetic and comparisons involving nil produce
+// nil rather than failing or treating it as an empty string, so missing fields in sparse data make the whole
+// comparison unknown. && and || follow three-valued logic, so false && nil is false and true || nil is true, but
+// true && nil is unknown. ParseAsBool treats an unknown result as false, use is_null(x) in expressions or IsNull on
+// the result of ParseAsAny to check for one. Call this before parsing any expressions
+func (m *Parser) EnableNullSemantics() {
+	m.semantics.Nulls = true
+}
+
+// now returns the current time according to the parser's clock
+func (m *Parser) now() time.Time {
+	return m.clock()
 }
 
 // Close releases the underlying resources
@@ -37,9 +71,16 @@ func (m *Parser) Close() {
 	m.cache.Close()
 }
 
-// ParseAsBool is used to test whether the incoming data matches the given expression. Any numeric value over 0, or strings evaluating to true will match
+// ParseAsBool is used to test whether the incoming data matches the given expression. Any numeric value over 0, or strings evaluating to true will match,
+// unless the coercion policy says otherwise. With null semantics enabled an unknown result doesn't match
 func (m *Parser) ParseAsBool(str string, data map[string]any) (bool, error) {
-	return parseAs(m, str, data, helpers.ToBool)
+	return parseAs(m, str, data, func(e any) (bool, error) {
+		if m.semantics.Nulls && nodes.IsNull(e) {
+			return false, nil
+		}
+
+		return m.semantics.Coercion.ToBool(e) //nolint:wrapcheck // Error does not need to be wrapped here
+	})
 }
 
 // ParseAsString will parse and evaluate the expression provided. Returning the result as a string, if the expression results in any other type it will be printed as a string
@@ -49,7 +90,12 @@ func (m *Parser) ParseAsString(str string, data map[string]any) (string, error)
 
 // ParseAsFloat will parse and evaluate the expression provided. Returning the result as a float64
 func (m *Parser) ParseAsFloat(str string, data map[string]any) (float64, error) {
-	return parseAs(m, str, data, helpers.ToFloat64)
+	return parseAs(m, str, data, m.semantics.Coercion.ToFloat64)
+}
+
+// ParseAsInt will parse and evaluate the expression provided. Returning the result as an int64, which must be a whole number
+func (m *Parser) ParseAsInt(str string, data map[string]any) (int64, error) {
+	return parseAs(m, str, data, m.semantics.Coercion.ToInt64)
 }
 
 // ParseAsAny will parse and evaluate the expression provided. Returning the result as a whichever type is most appropriate
@@ -58,25 +104,70 @@ func (m *Parser) ParseAsAny(str string, data map[string]any) (any, error) {
 }
 
 func parseAs[T any](m *Parser, str string, data map[string]any, converter func(e any) (T, error)) (T, error) {
-	node, found := m.cache.Get(str)
-	if !found {
-		var err error
-		node, err = parse(str, m.Registry)
-		if err != nil {
-			return *new(T), err
-		}
-
-		m.cache.Set(str, node)
+	node, err := m.node(str)
+	if err != nil {
+		return *new(T), err
 	}
 
+	start := time.Now()
 	val, err := node.Eval(data)
+	m.stats.evaluated(str, time.Since(start), err)
 	if err != nil {
+		m.logger.Debug("expression failed to evaluate", "expression", str, "error", err)
 		return *new(T), fmt.Errorf("error evaluating expression: %w", err)
 	}
 
 	return converter(val)
 }
 
+// cachedNode is a parsed expression along with the version of the registry it was parsed with
+type cachedNode struct {
+	nodes.Node
+	version uint64
+}
+
+// node returns the parsed expression, from the cache if it has been seen since the registry last changed
+func (m *Parser) node(str string) (nodes.Node, error) {
+	reg := m.Registry.load()
+	version := reg.version
+	cached, found := m.cache.Get(str)
+	c, ok := cached.(*cachedNode)
+	hit := found && ok && c.version == version
+
+	m.stats.cacheLookup(str, hit)
+	if hit {
+		return c.Node, nil
+	}
+
+	start := time.Now()
+	node, err := parse(str, reg, environment{now: m.now, semantics: m.semantics}, m.limits)
+	if err == nil && m.strict {
+		_, err = nodes.TypeOf(node, m.schema.Type())
+	}
+
+	d := time.Since(start)
+	m.stats.parsed(str, d, err)
+	if err != nil {
+		m.logger.Debug("expression failed to parse", "expression", str, "error", err)
+		return nil, err
+	}
+
+	m.logger.Debug("parsed expression", "expression", str, "duration", d)
+	m.cache.Set(str, &cachedNode{node, version})
+
+	return node, nil
+}
+
+// ClearCache removes every parsed expression from the cache
+func (m *Parser) ClearCache() {
+	m.cache.Clear()
+}
+
+// Evict removes the parsed expression from the cache, so that it is parsed again when it is next used
+func (m *Parser) Evict(str string) {
+	m.cache.Delete(str)
+}
+
 // ErrCacheSetup is returned when setting up the cache fails
 const ErrCacheSetup = cache.ErrCacheSetup
 
@@ -86,12 +177,32 @@ const ErrComparisonFailed = nodes.ErrComparisonFailed
 // ErrNodeEvalFailed is returned when a node failed to evaluate correctly
 const ErrNodeEvalFailed = nodes.ErrNodeEvalFailed
 
+// ErrFunctionPanicked is returned when a registered function or node panics, the error will be a *PanicError
+const ErrFunctionPanicked = nodes.ErrFunctionPanicked
+
+// ErrIntegerOverflow is returned when the result of integer arithmetic doesn't fit in an int64
+const ErrIntegerOverflow = nodes.ErrIntegerOverflow
+
+// ErrDivisionByZero is returned when an integer or decimal is divided by zero, or the remainder of a division by zero
+// is taken
+const ErrDivisionByZero = nodes.ErrDivisionByZero
+
+// PanicError holds the name of the function or node which panicked, the recovered value and the stack trace
+type PanicError = nodes.PanicError
+
 // TypesMatch check if the types of the two values are the same
 func TypesMatch(a, b any) bool { return helpers.TypesMatch(a, b) }
 
 // ToFloat64 attempts to convert the input value in to a float64. It will cast int/uint/flaot types, and attempt to parse strings as floats
 func ToFloat64(input any) (float64, error) { return helpers.ToFloat64(input) } //nolint:wrapcheck // Error does not need to be wrapped here
 
+// ToInt64 attempts to convert the input value in to an int64. Integers are converted exactly, other numbers and strings must hold a whole number
+func ToInt64(input any) (int64, error) { return helpers.ToInt64(input) } //nolint:wrapcheck // Error does not need to be wrapped here
+
+// IsNull checks whether the value is nil, or a nil pointer, such as the unknown result of a comparison with null
+// semantics enabled
+func IsNull(v any) bool { return nodes.IsNull(v) }
+
 // ToBool converts the input value in to a bool.
 //
 // - If the type is a number then any value over 0 will return true
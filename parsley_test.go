@@ -2,6 +2,7 @@ package parsley
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -437,6 +438,30 @@ func TestParse(t *testing.T) {
 			expectedAny:    "note_",
 			expectedString: "note_",
 		},
+		{
+			name:           "comparison binds looser than arithmetic",
+			input:          "1 + 2 > 2 && 3 * 2 == 6",
+			data:           map[string]any{},
+			expectedBool:   toPtr(true),
+			expectedAny:    true,
+			expectedString: "true",
+		},
+		{
+			name:           "and binds tighter than or",
+			input:          "1 == 1 || 1 == 2 && 1 == 2",
+			data:           map[string]any{},
+			expectedBool:   toPtr(true),
+			expectedAny:    true,
+			expectedString: "true",
+		},
+		{
+			name:           "left associative subtraction",
+			input:          "10 - 4 - 3",
+			data:           map[string]any{},
+			expectedBool:   toPtr(true),
+			expectedAny:    float64(3),
+			expectedString: "3",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -480,3 +505,53 @@ func TestParse(t *testing.T) {
 func toPtr[T any](t T) *T {
 	return &t
 }
+
+func TestDeepEquality(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	data := map[string]any{
+		"labels":  []any{"bug", "urgent"},
+		"ids":     []int{1, 2, 3},
+		"copy":    []float64{1, 2, 3},
+		"user":    map[string]any{"id": 3, "name": "User"},
+		"author":  user{ID: 3, Name: "User"},
+		"version": []any{1, 10, 2},
+		"blanks":  []any{nil, "bug"},
+	}
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`ids == copy`, true, nil},
+		{`ids == labels`, false, nil},
+		{`user == author`, true, nil},
+		{`"bug" in labels`, true, nil},
+		{`"feature" in labels`, false, nil},
+		{`3 in ids && 4 in ids`, false, nil},
+		{`"name" in user`, true, nil},
+		{`"Use" in user.name`, true, nil},
+		{`"bug" in missing`, false, nil},
+		{`missing == other`, true, nil},
+		{`missing in blanks`, true, nil},
+		{`version > copy`, true, nil},
+		{`version < copy`, false, nil},
+		{`user < author`, nil, errors.New("error evaluating expression: node evaluation failed: error running comparison: cannot order map[string]interface {} and parsley.user")},
+		{`1 in 2`, nil, errors.New("error evaluating expression: node evaluation failed: error running comparison: cannot look for a value in float64")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, data)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
@@ -2,10 +2,15 @@ package parsley
 
 import (
 	"fmt"
+	"maps"
 	"math"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
 
-	"github.com/scottkgregory/parsley/internal/helpers"
 	"github.com/scottkgregory/parsley/internal/nodes"
+	"github.com/scottkgregory/parsley/internal/types"
 )
 
 // Node defines the interface for new nodes to adhere
@@ -19,101 +24,424 @@ type UnaryNodeFunc func(right Node) Node
 
 // BinaryNodeFunc is a constructor for a binary node
 type BinaryNodeFunc func(left, right Node) Node
-type registry struct {
+
+// Registry holds the functions and nodes available to expressions. Expressions are parsed using an immutable
+// snapshot, and registering replaces the snapshot with an updated copy, so registering is safe while expressions are
+// being parsed and evaluated. A registry can be shared as the base for several parsers, see WithRegistry
+type Registry struct {
+	mu      sync.Mutex
+	current atomic.Pointer[snapshot]
+}
+
+// snapshot is the content of the registry at a point in time, it must not be changed once it is current
+type snapshot struct {
 	knownTokens []string
+	wordTokens  []string
 	unaryNodes  map[string]UnaryNodeFunc
 	binaryNodes map[string]BinaryNodeFunc
+	precedence  map[string]Precedence
+	postfix     map[string]PostfixNodeFunc
+	nary        map[string]naryNode
+	hooks       map[string]parseHook
 	functions   map[string]Function
+	binders     map[string]func(env environment) Function
+	signatures  map[string]Signature
+	docs        map[string]FunctionDoc
+	imports     []moduleImport
+
+	// version changes whenever anything is registered, so that expressions parsed beforehand can be parsed again.
+	// Versions are unique across registries, including clones, so a cache can be shared by parsers with different
+	// registries
+	version uint64
 }
 
-func newRegistry() *registry {
-	return &registry{
-		[]string{`+`, `-`, `*`, `^`, `/`, `(`, `)`, `""`, `,`, `==`, `>`, `<`, `&&`, `||`},
-		map[string]UnaryNodeFunc{},
-		map[string]BinaryNodeFunc{},
-		map[string]Function{
-			"ceil": func(args ...any) (any, error) {
-				x, err := helpers.ToFloat64(args[0])
-				if err != nil {
-					return nil, fmt.Errorf("error calling function ceil: %w", err)
-				}
-				return math.Ceil(x), nil
-			},
-			"floor": func(args ...any) (any, error) {
-				x, err := helpers.ToFloat64(args[0])
-				if err != nil {
-					return nil, fmt.Errorf("error calling function floor: %w", err)
-				}
-				return math.Floor(x), nil
-			},
-			"round": func(args ...any) (any, error) {
-				x, err := helpers.ToFloat64(args[0])
-				if err != nil {
-					return nil, fmt.Errorf("error calling function round: %w", err)
-				}
-				return math.Round(x), nil
-			},
-			"truncate": func(args ...any) (any, error) {
-				x, err := helpers.ToFloat64(args[0])
-				if err != nil {
-					return nil, fmt.Errorf("error calling function truncate: %w", err)
-				}
-				return math.Trunc(x), nil
-			},
-			"absolute": func(args ...any) (any, error) {
-				x, err := helpers.ToFloat64(args[0])
-				if err != nil {
-					return nil, fmt.Errorf("error calling function absolute: %w", err)
-				}
-				return math.Abs(x), nil
-			},
-			"contains_any": func(args ...any) (any, error) {
-				if args[0] == nil {
-					return false, nil
-				}
-
-				arg1, ok := args[1].(string)
-				if !ok {
-					return false, fmt.Errorf("second argument to contains_any was nil")
-				}
-
-				arr, ok := args[0].([]any)
-				if !ok {
-					return false, fmt.Errorf("first argument to contains_any was not []any")
-				}
-				for _, v := range arr {
-					match, err := nodes.Calculate("==", v.(map[string]any)[arg1], args[2])
-					if err != nil {
-						return false, fmt.Errorf("error calling function contains_any: %w", err)
-					}
-					if match.(bool) {
-						return match, nil
-					}
-				}
-
-				return false, nil
-			},
-			"not": func(args ...any) (any, error) {
-				a := args[0].(bool)
-				return !a, nil
-			},
+// versions is the source of snapshot versions
+var versions atomic.Uint64
+
+// builtinTokens are the tokens understood by the parser itself, they stay known when registered nodes are removed
+var builtinTokens = []string{`+`, `-`, `*`, `^`, `/`, `%`, `(`, `)`, `,`, `==`, `>`, `<`, `&&`, `||`, `=~`, `!~`, `=>`}
+
+// builtinWordTokens are the word operators understood by the parser itself
+var builtinWordTokens = []string{`in`}
+
+// NewRegistry creates a registry holding the built-in functions
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.current.Store(&snapshot{
+		knownTokens: slices.Clone(builtinTokens),
+		wordTokens:  slices.Clone(builtinWordTokens),
+		unaryNodes:  map[string]UnaryNodeFunc{},
+		binaryNodes: map[string]BinaryNodeFunc{},
+		precedence:  map[string]Precedence{},
+		postfix:     map[string]PostfixNodeFunc{},
+		nary:        map[string]naryNode{},
+		hooks:       map[string]parseHook{},
+		functions:   map[string]Function{},
+		binders:     map[string]func(env environment) Function{},
+		signatures:  map[string]Signature{},
+		docs:        map[string]FunctionDoc{},
+		version:     versions.Add(1),
+	})
+
+	RegisterFunc1(r, "ceil", mathFunc(math.Ceil))
+	RegisterFunc1(r, "floor", mathFunc(math.Floor))
+	RegisterFunc1(r, "round", mathFunc(math.Round))
+	RegisterFunc1(r, "truncate", mathFunc(math.Trunc))
+	RegisterFunc1(r, "absolute", mathFunc(math.Abs))
+	registerBound(r, "not", func(env environment) Function { return not(env.semantics) }, Signature{
+		Params:  []Param{{Name: "a", Type: TypeAny}},
+		Returns: TypeBool,
+	})
+	RegisterFunc1(r, "is_null", func(x any) (bool, error) { return nodes.IsNull(x), nil })
+
+	r.RegisterFunction("contains_any", func(args ...any) (any, error) {
+		key, ok := args[1].(string)
+		if !ok {
+			return false, fmt.Errorf("error calling function contains_any: key must be a string, got %T", args[1])
+		}
+
+		if args[0] == nil {
+			return false, nil
+		}
+
+		rv := reflect.ValueOf(args[0])
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return false, fmt.Errorf("error calling function contains_any: first argument must be a list, got %T", args[0])
+		}
+
+		for i := range rv.Len() {
+			v, ok := nodes.Field(rv.Index(i).Interface(), key)
+			if ok && nodes.Equal(v, args[2]) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}, Signature{
+		Params: []Param{
+			{Name: "list", Type: ListOf(TypeAny)},
+			{Name: "key", Type: TypeString},
+			{Name: "value", Type: TypeAny},
 		},
+		Returns: TypeBool,
+	})
+
+	return r
+}
+
+// not negates a bool. With null semantics the negation of an unknown value is unknown, as with the operators
+func not(semantics *nodes.Semantics) Function {
+	return func(args ...any) (any, error) {
+		if semantics.Nulls && nodes.IsNull(args[0]) {
+			return nil, nil
+		}
+
+		x, err := convertArg[bool]("not", 0, args[0], semantics.Coercion)
+		if err != nil {
+			return nil, err
+		}
+
+		return !x, nil
 	}
 }
 
-// RegisterUnaryNode adds a new unary node to the registry
+func mathFunc(fun func(float64) float64) func(float64) (float64, error) {
+	return func(x float64) (float64, error) {
+		return fun(x), nil
+	}
+}
+
+// Clone returns a copy of the registry. Anything registered afterwards in either one isn't seen by the other
+func (r *Registry) Clone() *Registry {
+	// The copy gets its own version, as parsers using it may be configured differently to those using the original
+	s := *r.load()
+	s.version = versions.Add(1)
+
+	c := &Registry{}
+	c.current.Store(&s)
+
+	return c
+}
+
+// Merge registers every function and node in the other registry in to this one, and imports the same modules. Those
+// with the same name or token as one already registered will replace it
+func (r *Registry) Merge(other *Registry) {
+	o := other.load()
+	r.update(func(s *snapshot) {
+		for _, tok := range o.knownTokens {
+			if !slices.Contains(s.knownTokens, tok) {
+				s.knownTokens = append(s.knownTokens, tok)
+			}
+		}
+
+		for _, tok := range o.wordTokens {
+			if !slices.Contains(s.wordTokens, tok) {
+				s.wordTokens = append(s.wordTokens, tok)
+			}
+		}
+
+		for _, imp := range o.imports {
+			if !slices.Contains(s.imports, imp) {
+				s.imports = append(s.imports, imp)
+			}
+		}
+
+		maps.Copy(s.unaryNodes, o.unaryNodes)
+		maps.Copy(s.binaryNodes, o.binaryNodes)
+		maps.Copy(s.precedence, o.precedence)
+		maps.Copy(s.postfix, o.postfix)
+		maps.Copy(s.nary, o.nary)
+		maps.Copy(s.hooks, o.hooks)
+		for name, fun := range o.functions {
+			s.functions[name] = fun
+			delete(s.binders, name)
+			if bind, ok := o.binders[name]; ok {
+				s.binders[name] = bind
+			}
+
+			delete(s.signatures, name)
+			if sig, ok := o.signatures[name]; ok {
+				s.signatures[name] = sig
+			}
+
+			delete(s.docs, name)
+			if doc, ok := o.docs[name]; ok {
+				s.docs[name] = doc
+			}
+		}
+	})
+}
+
+// load returns the current content of the registry
+func (r *Registry) load() *snapshot {
+	return r.current.Load()
+}
+
+// update makes a change to a copy of the current snapshot, then makes the copy current. Writers are serialised so
+// that no change is lost
+func (r *Registry) update(change func(s *snapshot)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.current.Load().clone()
+	change(s)
+	s.version = versions.Add(1)
+
+	r.current.Store(s)
+}
+
+func (s *snapshot) clone() *snapshot {
+	return &snapshot{
+		knownTokens: slices.Clone(s.knownTokens),
+		wordTokens:  slices.Clone(s.wordTokens),
+		unaryNodes:  maps.Clone(s.unaryNodes),
+		binaryNodes: maps.Clone(s.binaryNodes),
+		precedence:  maps.Clone(s.precedence),
+		postfix:     maps.Clone(s.postfix),
+		nary:        maps.Clone(s.nary),
+		hooks:       maps.Clone(s.hooks),
+		functions:   maps.Clone(s.functions),
+		binders:     maps.Clone(s.binders),
+		signatures:  maps.Clone(s.signatures),
+		docs:        maps.Clone(s.docs),
+		imports:     slices.Clone(s.imports),
+		version:     s.version,
+	}
+}
+
+// RegisterUnaryNode adds a new unary node to the registry. Tokens which look like identifiers, such as not, are only
+// recognised as whole words
 func (p *Parser) RegisterUnaryNode(token string, fun UnaryNodeFunc) {
-	p.Registry.knownTokens = append(p.Registry.knownTokens, token)
-	p.Registry.unaryNodes[token] = fun
+	p.Registry.RegisterUnaryNode(token, fun)
+}
+
+// RegisterUnaryNode adds a new unary node to the registry
+func (r *Registry) RegisterUnaryNode(token string, fun UnaryNodeFunc) {
+	r.update(func(s *snapshot) {
+		s.addToken(token)
+		s.unaryNodes[token] = fun
+	})
+}
+
+// UnregisterUnaryNode removes a unary node added with RegisterUnaryNode
+func (p *Parser) UnregisterUnaryNode(token string) {
+	p.Registry.UnregisterUnaryNode(token)
+}
+
+// UnregisterUnaryNode removes a unary node added with RegisterUnaryNode
+func (r *Registry) UnregisterUnaryNode(token string) {
+	r.update(func(s *snapshot) {
+		delete(s.unaryNodes, token)
+		s.forgetToken(token)
+	})
+}
+
+// RegisterBinaryNode adds a new binary node to the registry. By default the operator binds like + and is left
+// associative, an optional precedence can be given instead, such as
+//
+//	Precedence{Level: PrecedenceMultiplicative + 1, Associativity: RightAssociative}
+//
+// for a power operator which binds more tightly than *. Tokens which look like identifiers, such as contains, are only
+// recognised as whole words, so that names such as containsX are still identifiers
+func (p *Parser) RegisterBinaryNode(token string, fun BinaryNodeFunc, prec ...Precedence) {
+	p.Registry.RegisterBinaryNode(token, fun, prec...)
+}
+
+// RegisterBinaryNode adds a new binary node to the registry, see Parser.RegisterBinaryNode
+func (r *Registry) RegisterBinaryNode(token string, fun BinaryNodeFunc, prec ...Precedence) {
+	r.update(func(s *snapshot) {
+		s.addToken(token)
+		s.binaryNodes[token] = fun
+		s.precedence[token] = defaultPrecedence
+		if len(prec) > 0 {
+			s.precedence[token] = prec[0]
+		}
+	})
+}
+
+// UnregisterBinaryNode removes a binary node added with RegisterBinaryNode
+func (p *Parser) UnregisterBinaryNode(token string) {
+	p.Registry.UnregisterBinaryNode(token)
+}
+
+// UnregisterBinaryNode removes a binary node added with RegisterBinaryNode
+func (r *Registry) UnregisterBinaryNode(token string) {
+	r.update(func(s *snapshot) {
+		delete(s.binaryNodes, token)
+		delete(s.precedence, token)
+		s.forgetToken(token)
+	})
+}
+
+// addToken adds the token to the known tokens. Tokens which look like identifiers, such as contains, are word tokens
+// which are only recognised as whole words, so that names such as containsX are still identifiers
+func (s *snapshot) addToken(token string) {
+	if isWord(token) {
+		if !slices.Contains(s.wordTokens, token) {
+			s.wordTokens = append(s.wordTokens, token)
+		}
+
+		return
+	}
+
+	if !slices.Contains(s.knownTokens, token) {
+		s.knownTokens = append(s.knownTokens, token)
+	}
+}
+
+// forgetToken removes the token from the known tokens, unless it is still in use
+func (s *snapshot) forgetToken(token string) {
+	if s.uses(token) || slices.Contains(builtinTokens, token) || slices.Contains(builtinWordTokens, token) {
+		return
+	}
+
+	s.knownTokens = slices.DeleteFunc(s.knownTokens, func(tok string) bool { return tok == token })
+	s.wordTokens = slices.DeleteFunc(s.wordTokens, func(tok string) bool { return tok == token })
+}
+
+// uses checks whether anything registered uses the token
+func (s *snapshot) uses(token string) bool {
+	_, unary := s.unaryNodes[token]
+	_, binary := s.binaryNodes[token]
+	_, postfix := s.postfix[token]
+	_, hook := s.hooks[token]
+	if unary || binary || postfix || hook {
+		return true
+	}
+
+	for _, n := range s.nary {
+		if slices.Contains(n.keywords, token) {
+			return true
+		}
+	}
+
+	for _, h := range s.hooks {
+		if slices.Contains(h.tokens, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RegisterFunction registers a new function in the available set. Repeated calls will result in the latest one being registered.
+// Registering anything means cached expressions are parsed again, so that they use the latest functions and nodes.
+//
+// An optional signature can be given, in which case calls are checked for the right number of arguments when the
+// expression is parsed, and arguments are converted to the parameter types before the function is called. Registering
+// a signature with a required parameter after an optional one panics with ErrInvalidSignature, as no call could
+// leave out the optional parameter
+func (p *Parser) RegisterFunction(name string, fun Function, sig ...Signature) {
+	p.Registry.RegisterFunction(name, fun, sig...)
+}
+
+// RegisterFunction registers a new function in the available set, see Parser.RegisterFunction
+func (r *Registry) RegisterFunction(name string, fun Function, sig ...Signature) {
+	mustValidate(name, sig)
+	r.update(func(s *snapshot) {
+		s.functions[name] = fun
+		delete(s.binders, name)
+		delete(s.signatures, name)
+		delete(s.docs, name)
+		if len(sig) > 0 {
+			s.signatures[name] = sig[0]
+		}
+	})
+}
+
+func (p *Parser) registerBound(name string, bind func(env environment) Function, sig ...Signature) {
+	p.Registry.registerBound(name, bind, sig...)
 }
 
-// RegisterBinaryNode adds a new binary node to the registry
-func (p *Parser) RegisterBinaryNode(token string, fun BinaryNodeFunc) {
-	p.Registry.knownTokens = append(p.Registry.knownTokens, token)
-	p.Registry.binaryNodes[token] = fun
+// registerBound registers a function along with how to bind it to the settings of a parser. Parsers call bind with
+// their own environment when an expression calling the function is parsed, while the function bound to the default
+// environment is kept for callers outside of a parser
+func (r *Registry) registerBound(name string, bind func(env environment) Function, sig ...Signature) {
+	mustValidate(name, sig)
+	fun := bind(defaultEnvironment())
+	r.update(func(s *snapshot) {
+		s.functions[name] = fun
+		s.binders[name] = bind
+		delete(s.signatures, name)
+		delete(s.docs, name)
+		if len(sig) > 0 {
+			s.signatures[name] = sig[0]
+		}
+	})
 }
 
-// RegisterFunction registers a new function in the available set. Repeated calls will result in the latest one being registered
-func (p *Parser) RegisterFunction(name string, fun Function) {
-	p.Registry.functions[name] = fun
+// mustValidate panics when the signature is invalid, a mistake in the code registering the function rather than in
+// an expression
+func mustValidate(name string, sig []Signature) {
+	if len(sig) == 0 {
+		return
+	}
+
+	if err := sig[0].Validate(name); err != nil {
+		panic(err)
+	}
+}
+
+// UnregisterFunction removes a function, including a built-in one, so that expressions calling it fail to parse
+func (p *Parser) UnregisterFunction(name string) {
+	p.Registry.UnregisterFunction(name)
+}
+
+// UnregisterFunction removes a function, see Parser.UnregisterFunction
+func (r *Registry) UnregisterFunction(name string) {
+	r.update(func(s *snapshot) {
+		delete(s.functions, name)
+		delete(s.binders, name)
+		delete(s.signatures, name)
+		delete(s.docs, name)
+	})
+}
+
+func (s *snapshot) signature(name string) *types.Signature {
+	sig, ok := s.signatures[name]
+	if !ok {
+		return nil
+	}
+
+	return &sig
 }
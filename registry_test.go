@@ -1,7 +1,9 @@
 package parsley
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/scottkgregory/parsley/internal/assert"
@@ -56,7 +58,24 @@ func TestRegiserFunction(t *testing.T) {
 			[]struct{ name string }{{name: "foo"}, {name: "bar"}},
 			"name",
 			"bar",
-		}, fmt.Errorf("first argument to contains_any was not []any"), false, ""},
+		}, nil, false, ""},
+		{"contains_any", nil, []any{
+			[]struct {
+				Name string `json:"name"`
+			}{{Name: "foo"}, {Name: "bar"}},
+			"name",
+			"bar",
+		}, nil, true, ""},
+		{"contains_any", nil, []any{
+			[]any{map[string]any{"id": 3}, map[string]any{"id": []any{1, 2}}},
+			"id",
+			[]int{1, 2},
+		}, nil, true, ""},
+		{"contains_any", nil, []any{
+			[]any{map[string]any{"id": 3}},
+			"id",
+			"3",
+		}, nil, false, ""},
 		{"contains_any", nil, []any{
 			[]any{map[string]any{"name": "foo"}, map[string]any{"name": "bar"}},
 			"name",
@@ -72,13 +91,25 @@ func TestRegiserFunction(t *testing.T) {
 			"name",
 			"baz",
 		}, nil, false, ""},
+		{"contains_any", nil, []any{nil, "name", "baz"}, nil, false, ""},
+		{"contains_any", nil, []any{"foo", "name", "baz"}, errors.New("error calling function contains_any: first argument must be a list, got string"), false, ""},
+		{"contains_any", nil, []any{
+			[]any{map[string]any{"name": "foo"}, map[string]any{}},
+			"name",
+			nil,
+		}, nil, false, ""},
+		{"contains_any", nil, []any{
+			[]any{map[string]any{"name": "foo"}, map[string]any{"name": nil}},
+			"name",
+			nil,
+		}, nil, true, ""},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			parser, err := NewParser(false)
 			assert.Nil(t, err)
 
-			actual, err := parser.Registry.functions[tc.name](tc.args...)
+			actual, err := parser.Registry.load().functions[tc.name](tc.args...)
 			assert.Equal(t, tc.result, actual)
 			assert.ErrorEqual(t, tc.err, err)
 
@@ -98,3 +129,380 @@ func (t *testNode) Eval(_ map[string]any) (any, error) {
 func (t *testNode) String() string {
 	return "£"
 }
+
+func TestRegisterFunctionWithSignature(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.RegisterFunction("join", func(args ...any) (any, error) {
+		sep := args[0].(string)
+		ret := ""
+		for i, a := range args[1:] {
+			if i > 0 {
+				ret += sep
+			}
+			ret += a.(string)
+		}
+		return ret, nil
+	}, Signature{
+		Params:   []Param{{Name: "sep", Type: TypeString}, {Name: "parts", Type: TypeString}},
+		Variadic: true,
+		Returns:  TypeString,
+	})
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`join(sep, 1, 2, 3)`, "1-2-3", nil},
+		{`join(sep)`, "", nil},
+		{`join()`, nil, errors.New("invalid number of arguments: function join expects at least 1 arguments, got 0")},
+		{`ceil()`, nil, errors.New("invalid number of arguments: function ceil expects 1 arguments, got 0")},
+		{`floor(1, 2)`, nil, errors.New("invalid number of arguments: function floor expects 1 arguments, got 2")},
+		{`not(1)`, false, nil},
+		{`not(0)`, true, nil},
+		{`not(foo)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function not: error parsing value as bool, invalid type: <nil>")},
+		{`contains_any(foo, "a", 1)`, false, nil},
+		{`contains_any(bar, "a", 1)`, true, nil},
+		{`contains_any(baz, "a", 1)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function contains_any: expected list, got string")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, map[string]any{
+				"bar": []map[string]any{{"a": 1}},
+				"baz": "a",
+				"sep": "-",
+			})
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestRegisterFunctionWithInvalidSignature(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	defer func() {
+		err, _ := recover().(error)
+		assert.ErrorEqual(t, errors.New("invalid signature: function pad has required parameter width after optional parameter char"), err)
+		assert.ErrorIs(t, ErrInvalidSignature, err)
+
+		_, ok := parser.Registry.load().functions["pad"]
+		assert.Equal(t, false, ok)
+	}()
+
+	parser.RegisterFunction("pad", func(args ...any) (any, error) { return args[0], nil }, Signature{
+		Params: []Param{
+			{Name: "s", Type: TypeString},
+			{Name: "char", Type: TypeString, Optional: true},
+			{Name: "width", Type: TypeNumber},
+		},
+		Returns: TypeString,
+	})
+}
+
+func TestRegisteredPanics(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.RegisterFunction("boom", func(_ ...any) (any, error) { panic("boom") })
+	parser.RegisterUnaryNode("£", func(_ nodes.Node) nodes.Node { panic("constructor") })
+	parser.RegisterBinaryNode("$", func(_, _ nodes.Node) nodes.Node { return &panicNode{} })
+
+	testCases := []struct {
+		input string
+		err   error
+	}{
+		{`boom()`, errors.New("error evaluating expression: node evaluation failed: function panicked: boom: boom")},
+		{`£1`, errors.New("function panicked: £: constructor")},
+		{`1$2`, errors.New("error evaluating expression: function panicked: $: eval")},
+		{`(1$2) + 1`, errors.New("error evaluating expression: node evaluation failed, left side error: function panicked: $: eval")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, nil)
+			assert.Nil(t, actual)
+			assert.ErrorEqual(t, tc.err, err)
+			assert.ErrorIs(t, ErrFunctionPanicked, err)
+
+			var panicErr *PanicError
+			assert.Equal(t, true, errors.As(err, &panicErr))
+		})
+	}
+}
+
+type panicNode struct{}
+
+func (p *panicNode) Eval(_ map[string]any) (any, error) {
+	panic("eval")
+}
+
+func (p *panicNode) String() string {
+	return "$"
+}
+
+func TestConcurrentRegistry(t *testing.T) {
+	parser, err := New(WithLRUCache(16))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+
+		// Register new functions and operators while expressions are being parsed
+		go func() {
+			defer wg.Done()
+
+			for j := range 50 {
+				name := fmt.Sprintf("fn_%d_%d", i, j)
+				parser.RegisterFunction(name, func(_ ...any) (any, error) { return j, nil })
+				parser.RegisterBinaryNode(fmt.Sprintf("£%d", i), func(_, _ Node) Node { return &testNode{} })
+				parser.RegisterUnaryNode(fmt.Sprintf("¥%d", i), func(_ Node) Node { return &testNode{} })
+				parser.LoadLibrary(StringLibrary)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 200 {
+				actual, err := parser.ParseAsFloat(fmt.Sprintf("a + %d", j%8), map[string]any{"a": 1})
+				assert.Nil(t, err)
+				assert.Equal(t, float64(1+j%8), actual)
+
+				actual, err = parser.ParseAsFloat("ceil(a)", map[string]any{"a": 1.5})
+				assert.Nil(t, err)
+				assert.Equal(t, float64(2), actual)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i := range 4 {
+		actual, err := parser.ParseAsAny(fmt.Sprintf("fn_%d_49()", i), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 49, actual)
+	}
+}
+
+func TestRegistryClone(t *testing.T) {
+	base := NewRegistry()
+	base.RegisterFunction("tenant", func(_ ...any) (any, error) { return "base", nil })
+
+	clone := base.Clone()
+	clone.RegisterFunction("extra", func(_ ...any) (any, error) { return "clone", nil })
+	base.RegisterFunction("tenant", func(_ ...any) (any, error) { return "changed", nil })
+
+	_, ok := base.load().functions["extra"]
+	assert.Equal(t, false, ok)
+
+	actual, err := clone.load().functions["tenant"]()
+	assert.Nil(t, err)
+	assert.Equal(t, "base", actual)
+}
+
+func TestRegistryMerge(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterFunction("first", func(_ ...any) (any, error) { return 1, nil })
+
+	other := NewRegistry()
+	other.LoadLibrary(StringLibrary)
+	other.RegisterFunction("first", func(_ ...any) (any, error) { return 2, nil }, Signature{Returns: TypeNumber})
+	other.RegisterBinaryNode("£", func(_, _ Node) Node { return &testNode{} })
+
+	r.Merge(other)
+
+	parser, err := New(WithRegistry(r))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	testCases := []struct {
+		input  string
+		result any
+	}{
+		{`first()`, 2},
+		{`upper("a")`, "A"},
+		{`1 £ 2`, 12},
+		{`ceil(1.5)`, float64(2)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+
+	assert.Equal(t, true, r.load().signature("first") != nil)
+}
+
+func TestWithRegistry(t *testing.T) {
+	base := NewRegistry()
+	base.LoadLibrary(StringLibrary)
+
+	a, err := New(WithRegistry(base), WithCache())
+	assert.Nil(t, err)
+	defer a.Close()
+
+	b, err := New(WithRegistry(base))
+	assert.Nil(t, err)
+	defer b.Close()
+
+	a.RegisterFunction("tenant", func(_ ...any) (any, error) { return "a", nil })
+	b.RegisterFunction("tenant", func(_ ...any) (any, error) { return "b", nil })
+
+	testCases := []struct {
+		parser *Parser
+		result any
+	}{
+		{a, "A:a"},
+		{b, "B:b"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.result.(string), func(t *testing.T) {
+			actual, err := tc.parser.ParseAsAny(`upper(tenant()) + ":" + tenant()`, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+
+	_, ok := base.load().functions["tenant"]
+	assert.Equal(t, false, ok)
+
+	_, err = New(WithLibraries(MathLibrary), WithRegistry(base))
+	assert.ErrorEqual(t, errors.New("invalid option: registry must be given before options which register functions"), err)
+	assert.ErrorIs(t, ErrInvalidOption, err)
+
+	c, err := New(WithCoercion(CoercionStrict), WithRegistry(NewRegistry()), WithRegistry(base), WithLibraries(MathLibrary))
+	assert.Nil(t, err)
+	defer c.Close()
+
+	actual, err := c.ParseAsAny(`upper("a") == "A" && max(1, 2) == 2`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, true, actual)
+}
+
+func TestWithRegistrySharedCache(t *testing.T) {
+	base := NewRegistry()
+	store := NewLRUCache(10)
+	schema := NewSchema(map[string]Type{"name": TypeString})
+
+	plain, err := New(WithRegistry(base), WithCacheStore(store))
+	assert.Nil(t, err)
+
+	exact, err := New(WithRegistry(base), WithCacheStore(store), WithDecimal(DefaultDecimalContext))
+	assert.Nil(t, err)
+
+	strict, err := New(WithRegistry(base), WithCacheStore(store), WithStrict(schema))
+	assert.Nil(t, err)
+
+	actual, err := exact.ParseAsAny(`0.1 + 0.2`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.3", fmt.Sprint(actual))
+
+	actual, err = plain.ParseAsAny(`0.1 + 0.2`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.30000000000000004", fmt.Sprint(actual))
+
+	_, err = plain.ParseAsBool(`nmae == "main"`, nil)
+	assert.Nil(t, err)
+
+	_, err = strict.ParseAsBool(`nmae == "main"`, nil)
+	assert.ErrorIs(t, ErrTypeCheckFailed, err)
+}
+
+// opNode records how an expression was grouped
+type opNode struct {
+	op          string
+	left, right Node
+}
+
+func (n *opNode) Eval(_ map[string]any) (any, error) {
+	return nil, nil
+}
+
+func (n *opNode) String() string {
+	return "(" + n.left.String() + " " + n.op + " " + n.right.String() + ")"
+}
+
+func TestRegisterBinaryPrecedence(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	op := func(token string) BinaryNodeFunc {
+		return func(left, right Node) Node { return &opNode{token, left, right} }
+	}
+
+	parser.RegisterBinaryNode("**", op("**"), Precedence{Level: PrecedenceMultiplicative + 1, Associativity: RightAssociative})
+	parser.RegisterBinaryNode("<>", op("<>"), Precedence{Level: PrecedenceComparison})
+	parser.RegisterBinaryNode("£", op("£"))
+	parser.RegisterBinaryNode("@", op("@"), Precedence{Level: PrecedenceAdditive, Associativity: RightAssociative})
+
+	testCases := []struct {
+		input  string
+		result any
+	}{
+		{`2 ** 3 ** 2`, "(2 ** (3 ** 2))"},
+		{`a * b ** c`, "a*(b ** c)"},
+		{`a ** b * c`, "(a ** b)*c"},
+		{`a + b <> c`, "(a+b <> c)"},
+		{`a £ b £ c`, "((a £ b) £ c)"},
+		{`a £ b * c`, "(a £ b*c)"},
+		{`a @ b @ c`, "(a @ (b @ c))"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			node, err := parse(tc.input, parser.Registry.load(), defaultEnvironment(), Limits{})
+			assert.Nil(t, err)
+			assert.Equal(t, tc.result, node.String())
+		})
+	}
+}
+
+func TestRegisterWordOperators(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	parser.RegisterBinaryNode("contains", func(left, right Node) Node { return &opNode{"contains", left, right} },
+		Precedence{Level: PrecedenceComparison})
+	parser.RegisterUnaryNode("neg", func(right Node) Node { return nodes.NewUnaryNode(right, "-") })
+
+	testCases := []struct {
+		input  string
+		vars   map[string]any
+		result any
+		err    error
+	}{
+		{`name contains "foo"`, nil, nil, nil},
+		{`containsX + 1`, map[string]any{"containsX": 1}, float64(2), nil},
+		{`contains_any + 1`, map[string]any{"contains_any": 2}, float64(3), nil},
+		{`neg 2 + neg2`, map[string]any{"neg2": 1}, float64(-1), nil},
+		{`neg(3)`, nil, float64(-3), nil},
+		{`contains 1`, nil, nil, errors.New("unexpected token: contains")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, tc.vars)
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+
+	node, err := parse(`a + b contains "x" && c`, parser.Registry.load(), defaultEnvironment(), Limits{})
+	assert.Nil(t, err)
+	assert.Equal(t, `(a+b contains "x") && c`, node.String())
+
+	parser.UnregisterBinaryNode("contains")
+	actual, err := parser.ParseAsAny(`contains`, map[string]any{"contains": 4})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, actual)
+}
@@ -0,0 +1,124 @@
+package parsley
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/scottkgregory/parsley/internal/nodes"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+// Type describes the shape of a value, used to check expressions before they are evaluated
+type Type = types.Type
+
+// Param describes a single parameter of a function
+type Param = types.Param
+
+// Signature describes the parameters and return type of a function
+type Signature = types.Signature
+
+// Lambda is the value of a lambda argument such as x => x.name, calling it evaluates the body with x bound to arg
+type Lambda = nodes.Lambda
+
+// ErrTypeCheckFailed is returned when an expression is not valid for the schema it was checked against
+const ErrTypeCheckFailed = types.ErrTypeCheckFailed
+
+// ErrInvalidArity is returned when a function is called with the wrong number of arguments for its signature
+const ErrInvalidArity = types.ErrInvalidArity
+
+// ErrInvalidArgument is returned when an argument can't be converted to the type in the function signature
+const ErrInvalidArgument = types.ErrInvalidArgument
+
+// ErrInvalidSignature is returned when a function is registered with a required parameter after an optional one
+const ErrInvalidSignature = types.ErrInvalidSignature
+
+var (
+	// TypeAny is the type of a value which is not known until evaluation
+	TypeAny = types.Any
+	// TypeNull is the type of nil
+	TypeNull = types.Null
+	// TypeBool is the type of a boolean value
+	TypeBool = types.Bool
+	// TypeNumber is the type of a numeric value
+	TypeNumber = types.Number
+	// TypeString is the type of a string value
+	TypeString = types.String
+	// TypeRegexp is the type of a compiled regular expression, strings are compiled when passed as one
+	TypeRegexp = types.Regexp
+	// TypeTime is the type of a time, strings are parsed as RFC3339 when passed as one
+	TypeTime = types.Time
+	// TypeDuration is the type of a duration, strings such as "15m" are parsed when passed as one
+	TypeDuration = types.Duration
+	// TypeLambda is the type of a lambda such as x => x.name
+	TypeLambda = types.Lambda
+)
+
+// ListOf creates a list type with items of the given type
+func ListOf(elem Type) Type { return types.ListOf(elem) }
+
+// ObjectOf creates an object type with only the given fields
+func ObjectOf(fields map[string]Type) Type { return types.ObjectOf(fields) }
+
+// MapOf creates an object type with any field names, all of the given type
+func MapOf(elem Type) Type { return types.MapOf(elem) }
+
+// Schema describes the shape of the data expressions will be evaluated against
+type Schema struct {
+	root Type
+}
+
+// NewSchema creates a schema where the data contains only the given fields
+func NewSchema(fields map[string]Type) *Schema {
+	return &Schema{types.ObjectOf(fields)}
+}
+
+// SchemaFor creates a schema from the type of the given Go value, which should be a struct or a map with string keys.
+// Struct fields are named using their json tags where present
+func SchemaFor(v any) (*Schema, error) {
+	t, err := types.FromGo(reflect.TypeOf(v))
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Error is already wrapped
+	}
+
+	if !t.Is(types.KindObject) {
+		return nil, fmt.Errorf("%w: schema must be an object, got %s", ErrTypeCheckFailed, t)
+	}
+
+	return &Schema{t}, nil
+}
+
+// SchemaFromJSON creates a schema from a JSON Schema document. Objects with properties are treated as closed unless
+// additionalProperties is set, so that misspelt fields are caught
+func SchemaFromJSON(raw []byte) (*Schema, error) {
+	t, err := types.FromJSONSchema(raw)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Error is already wrapped
+	}
+
+	if !t.Is(types.KindObject, types.KindAny) {
+		return nil, fmt.Errorf("%w: schema must be an object, got %s", ErrTypeCheckFailed, t)
+	}
+
+	return &Schema{t}, nil
+}
+
+// Type returns the type of the data described by the schema
+func (s *Schema) Type() Type {
+	if s == nil {
+		return types.Any
+	}
+
+	return s.root
+}
+
+// Check parses the expression and checks it against the schema without evaluating it, returning the type it will
+// produce. A nil schema allows any variable. Checking is stricter than evaluation, strings are never assumed to hold
+// numbers for example, so that mistakes are caught when an expression is saved rather than when it is first run
+func (m *Parser) Check(str string, schema *Schema) (Type, error) {
+	node, err := m.node(str)
+	if err != nil {
+		return types.Any, err
+	}
+
+	return nodes.TypeOf(node, schema.Type()) //nolint:wrapcheck // Error is already wrapped
+}
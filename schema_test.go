@@ -0,0 +1,120 @@
+package parsley
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+type testEvent struct {
+	Ref  string `json:"ref"`
+	User struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"user"`
+	Labels []struct {
+		Title string `json:"title"`
+	} `json:"labels"`
+	Extra map[string]any `json:"extra"`
+}
+
+const testEventSchema = `{
+  "type": "object",
+  "properties": {
+    "ref": {"type": "string"},
+    "user": {
+      "type": "object",
+      "properties": {
+        "id": {"type": "integer"},
+        "name": {"type": "string"}
+      }
+    },
+    "labels": {
+      "type": "array",
+      "items": {"type": "object", "properties": {"title": {"type": "string"}}}
+    },
+    "extra": {"type": "object"}
+  }
+}`
+
+func TestCheck(t *testing.T) {
+	goSchema, err := SchemaFor(testEvent{})
+	assert.Nil(t, err)
+
+	jsonSchema, err := SchemaFromJSON([]byte(testEventSchema))
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		name   string
+		input  string
+		result Type
+		err    error
+	}{
+		{"string comparison", `user.name == "foo"`, TypeBool, nil},
+		{"number arithmetic", `user.id * 2`, TypeNumber, nil},
+		{"concat", `ref + user.name`, TypeString, nil},
+		{"function", `floor(user.id / 2)`, TypeNumber, nil},
+		{"contains_any", `contains_any(labels, "title", "automated")`, TypeBool, nil},
+		{"open object", `extra.anything.at.all > 2`, TypeBool, nil},
+		{"logic", `(user.id > 2) && (ref == "main")`, TypeBool, nil},
+		{"string vs number", `user.name > 3`, TypeAny, errors.New("type check failed: only one side of comparison was a string: string number")},
+		{"unknown field", `user.nme == "foo"`, TypeAny, errors.New("type check failed: unknown field nme in user.nme")},
+		{"unknown root", `usr.name == "foo"`, TypeAny, errors.New("type check failed: unknown field usr in usr")},
+		{"function type", `ceil(user.name)`, TypeAny, errors.New("type check failed: argument 0 to function ceil must be number, got string")},
+		{"list as bool", `labels && (ref == "main")`, TypeAny, errors.New("type check failed: cannot use list<object> as bool")},
+	}
+	for _, tc := range testCases {
+		for name, schema := range map[string]*Schema{"go": goSchema, "json": jsonSchema} {
+			t.Run(name+"_"+tc.name, func(t *testing.T) {
+				parser, err := NewParser(false)
+				assert.Nil(t, err)
+
+				actual, err := parser.Check(tc.input, schema)
+				assert.ErrorEqual(t, tc.err, err)
+				assert.Equal(t, tc.result.String(), actual.String())
+				if tc.err != nil {
+					assert.ErrorIs(t, ErrTypeCheckFailed, err)
+				}
+
+				parser.Close()
+			})
+		}
+	}
+}
+
+func TestCheckWithoutSchema(t *testing.T) {
+	parser, err := NewParser(true)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	actual, err := parser.Check(`foo.bar > 3`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, TypeBool.String(), actual.String())
+
+	_, err = parser.Check(`foo.bar >`, nil)
+	assert.ErrorEqual(t, errors.New("unexpected token: EOF"), err)
+
+	_, err = parser.Check(`floor(foo, 2)`, nil)
+	assert.ErrorEqual(t, errors.New("invalid number of arguments: function floor expects 1 arguments, got 2"), err)
+	assert.ErrorIs(t, ErrInvalidArity, err)
+
+	parser.RegisterFunction("ceil", func(_ ...any) (any, error) { return "", nil })
+	actual, err = parser.Check(`ceil(1, 2)`, NewSchema(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, TypeAny.String(), actual.String())
+}
+
+func TestSchemaErrors(t *testing.T) {
+	_, err := SchemaFor([]string{})
+	assert.ErrorEqual(t, errors.New("type check failed: schema must be an object, got list<string>"), err)
+
+	_, err = SchemaFor(map[int]string{})
+	assert.ErrorEqual(t, errors.New("type check failed: map keys must be strings, got int"), err)
+
+	_, err = SchemaFromJSON([]byte(`{"type": "string"}`))
+	assert.ErrorEqual(t, errors.New("type check failed: schema must be an object, got string"), err)
+
+	_, err = SchemaFromJSON([]byte(`{`))
+	assert.ErrorIs(t, ErrTypeCheckFailed, err)
+}
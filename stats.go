@@ -0,0 +1,197 @@
+package parsley
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/cache"
+)
+
+// CacheMetrics are the statistics a cache keeps about itself, such as ristretto when RistrettoConfig.Metrics is set
+type CacheMetrics = cache.Metrics
+
+// histogramBounds are the upper bounds of the buckets used for parse and evaluation times
+var histogramBounds = []time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// Histogram counts durations in buckets. Counts[i] is the number of durations no longer than Bounds[i], which weren't
+// counted in an earlier bucket. The final count is of durations longer than every bound
+type Histogram struct {
+	Bounds []time.Duration
+	Counts []uint64
+	Count  uint64
+	Sum    time.Duration
+}
+
+// Mean returns the average duration, or zero if nothing has been counted
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Stats are the statistics kept by a parser since it was created
+type Stats struct {
+	// CacheHits is the number of expressions found in the cache
+	CacheHits uint64
+	// CacheMisses is the number of expressions which had to be parsed
+	CacheMisses uint64
+	// Parses is the number of times an expression was parsed, including those which failed
+	Parses uint64
+	// ParseErrors is the number of expressions which failed to parse or type check
+	ParseErrors uint64
+	// ParseTime is how long parsing took
+	ParseTime Histogram
+	// Evals is the number of times an expression was evaluated, including those which failed
+	Evals uint64
+	// EvalErrors is the number of evaluations which returned an error
+	EvalErrors uint64
+	// EvalTime is how long evaluation took
+	EvalTime Histogram
+	// Cache holds the statistics kept by the cache itself, or nil if it doesn't keep any
+	Cache *CacheMetrics
+}
+
+// MetricsHook is notified as expressions are parsed and evaluated, so that the statistics can be exported to a metrics
+// system. Hooks are called synchronously and must be safe for concurrent use
+type MetricsHook interface {
+	// CacheLookup is called when a parsed expression is looked for in the cache
+	CacheLookup(expr string, hit bool)
+	// Parsed is called when an expression has been parsed, err is set if it failed
+	Parsed(expr string, d time.Duration, err error)
+	// Evaluated is called when an expression has been evaluated, err is set if it failed
+	Evaluated(expr string, d time.Duration, err error)
+}
+
+// WithMetricsHook notifies the hook as expressions are parsed and evaluated. The option can be given more than once
+func WithMetricsHook(hook MetricsHook) Option {
+	return func(m *Parser) error {
+		if hook == nil {
+			return fmt.Errorf("%w: metrics hook can't be nil", ErrInvalidOption)
+		}
+
+		m.stats.hooks = append(m.stats.hooks, hook)
+		return nil
+	}
+}
+
+// Stats returns the statistics kept since the parser was created
+func (m *Parser) Stats() Stats {
+	s := m.stats
+	ret := Stats{
+		CacheHits:   s.hits.Load(),
+		CacheMisses: s.misses.Load(),
+		Parses:      s.parses.Load(),
+		ParseErrors: s.parseErrors.Load(),
+		ParseTime:   s.parseTime.snapshot(),
+		Evals:       s.evals.Load(),
+		EvalErrors:  s.evalErrors.Load(),
+		EvalTime:    s.evalTime.snapshot(),
+	}
+
+	if store, ok := m.cache.(cache.MetricsStore); ok {
+		if metrics, ok := store.Metrics(); ok {
+			ret.Cache = &metrics
+		}
+	}
+
+	return ret
+}
+
+// stats counts events in a parser, and passes them on to any hooks
+type stats struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	parses      atomic.Uint64
+	parseErrors atomic.Uint64
+	parseTime   histogram
+	evals       atomic.Uint64
+	evalErrors  atomic.Uint64
+	evalTime    histogram
+	hooks       []MetricsHook
+}
+
+func newStats() *stats {
+	return &stats{parseTime: newHistogram(), evalTime: newHistogram()}
+}
+
+func (s *stats) cacheLookup(expr string, hit bool) {
+	if hit {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+
+	for _, hook := range s.hooks {
+		hook.CacheLookup(expr, hit)
+	}
+}
+
+func (s *stats) parsed(expr string, d time.Duration, err error) {
+	s.parses.Add(1)
+	if err != nil {
+		s.parseErrors.Add(1)
+	}
+
+	s.parseTime.observe(d)
+	for _, hook := range s.hooks {
+		hook.Parsed(expr, d, err)
+	}
+}
+
+func (s *stats) evaluated(expr string, d time.Duration, err error) {
+	s.evals.Add(1)
+	if err != nil {
+		s.evalErrors.Add(1)
+	}
+
+	s.evalTime.observe(d)
+	for _, hook := range s.hooks {
+		hook.Evaluated(expr, d, err)
+	}
+}
+
+type histogram struct {
+	counts []atomic.Uint64
+	sum    atomic.Int64
+}
+
+func newHistogram() histogram {
+	return histogram{counts: make([]atomic.Uint64, len(histogramBounds)+1)}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for i < len(histogramBounds) && d > histogramBounds[i] {
+		i++
+	}
+
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *histogram) snapshot() Histogram {
+	ret := Histogram{
+		Bounds: slices.Clone(histogramBounds),
+		Counts: make([]uint64, len(h.counts)),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+
+	for i := range h.counts {
+		ret.Counts[i] = h.counts[i].Load()
+		ret.Count += ret.Counts[i]
+	}
+
+	return ret
+}
@@ -0,0 +1,107 @@
+package parsley
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+type countingHook struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *countingHook) CacheLookup(expr string, hit bool) {
+	h.record("lookup", expr, hit)
+}
+
+func (h *countingHook) Parsed(expr string, _ time.Duration, err error) {
+	h.record("parsed", expr, err == nil)
+}
+
+func (h *countingHook) Evaluated(expr string, _ time.Duration, err error) {
+	h.record("evaluated", expr, err == nil)
+}
+
+func (h *countingHook) record(event, expr string, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events = append(h.events, event+" "+expr+" "+map[bool]string{true: "ok", false: "failed"}[ok])
+}
+
+func TestStats(t *testing.T) {
+	hook := &countingHook{}
+
+	parser, err := New(WithLRUCache(10), WithMetricsHook(hook))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	for _, input := range []string{`1 + 2`, `1 + 2`, `missing > 1`, `1 +`} {
+		_, _ = parser.ParseAsAny(input, nil)
+	}
+
+	stats := parser.Stats()
+	assert.Equal(t, uint64(1), stats.CacheHits)
+	assert.Equal(t, uint64(3), stats.CacheMisses)
+	assert.Equal(t, uint64(3), stats.Parses)
+	assert.Equal(t, uint64(1), stats.ParseErrors)
+	assert.Equal(t, uint64(3), stats.ParseTime.Count)
+	assert.Equal(t, uint64(3), stats.Evals)
+	assert.Equal(t, uint64(1), stats.EvalErrors)
+	assert.Equal(t, uint64(3), stats.EvalTime.Count)
+	assert.Equal(t, len(stats.EvalTime.Bounds)+1, len(stats.EvalTime.Counts))
+
+	assert.Equal(t, &CacheMetrics{Hits: 1, Misses: 3, KeysAdded: 2}, stats.Cache)
+
+	assert.Equal(t, []string{
+		"lookup 1 + 2 failed",
+		"parsed 1 + 2 ok",
+		"evaluated 1 + 2 ok",
+		"lookup 1 + 2 ok",
+		"evaluated 1 + 2 ok",
+		"lookup missing > 1 failed",
+		"parsed missing > 1 ok",
+		"evaluated missing > 1 failed",
+		"lookup 1 + failed",
+		"parsed 1 + failed",
+	}, hook.events)
+}
+
+func TestStatsRistretto(t *testing.T) {
+	store, err := NewRistrettoCache(RistrettoConfig{NumCounters: 1000, MaxCost: 100, BufferItems: 64, Metrics: true})
+	assert.Nil(t, err)
+
+	parser, err := New(WithCacheStore(store))
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	_, err = parser.ParseAsAny(`1 + 2`, nil)
+	assert.Nil(t, err)
+
+	stats := parser.Stats()
+	assert.Equal(t, uint64(1), stats.Cache.Misses)
+
+	parser, err = New()
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	assert.Equal(t, true, parser.Stats().Cache == nil)
+}
+
+func TestHistogram(t *testing.T) {
+	h := newHistogram()
+	h.observe(500 * time.Nanosecond)
+	h.observe(time.Microsecond)
+	h.observe(5 * time.Millisecond)
+	h.observe(time.Minute)
+
+	actual := h.snapshot()
+	assert.Equal(t, []uint64{2, 0, 0, 0, 1, 0, 0, 1}, actual.Counts)
+	assert.Equal(t, uint64(4), actual.Count)
+	assert.Equal(t, time.Minute+5*time.Millisecond+1500*time.Nanosecond, actual.Sum)
+	assert.Equal(t, actual.Sum/4, actual.Mean())
+	assert.Equal(t, time.Duration(0), Histogram{}.Mean())
+}
@@ -1,6 +1,7 @@
 package parsley
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"strconv"
@@ -14,34 +15,29 @@ const (
 	eof        = "EOF"
 	identifier = "Identifier"
 	number     = "Number"
+	str        = "String"
 )
 
 type tokenizer struct {
-	raw             string
-	runes           []rune
-	position        int
-	currentRune     rune
-	maxKnowTokenLen int
-	reg             *registry
+	raw         string
+	runes       []rune
+	position    int
+	currentRune rune
+	reg         *snapshot
 
 	Token      string
 	Number     float64
+	NumberText string
 	Identifier string
+	String     string
 }
 
-func newTokenizer(str string, reg *registry) (*tokenizer, error) {
+func newTokenizer(str string, reg *snapshot) (*tokenizer, error) {
 	t := &tokenizer{
 		raw:      str,
 		runes:    []rune(str),
 		position: 0,
-		maxKnowTokenLen: len(slices.MaxFunc(reg.knownTokens, func(x, y string) int {
-			if len(x) > len(y) {
-				return len(x)
-			}
-
-			return len(y)
-		})),
-		reg: reg,
+		reg:      reg,
 	}
 	t.NextRune()
 	err := t.NextToken()
@@ -61,40 +57,38 @@ func (t *tokenizer) NextToken() (err error) {
 	}
 
 	t.Token = ""
-	if slices.ContainsFunc(t.reg.knownTokens, func(tok string) bool {
-		return t.currentRune == []rune(tok)[0]
-	}) {
-		t.Token = string(t.currentRune)
-		t.NextRune()
 
-		for i := 1; i < t.maxKnowTokenLen; i++ {
-			if slices.ContainsFunc(t.reg.knownTokens, func(tok string) bool {
-				if len([]rune(tok)) >= i+1 {
-					return t.currentRune == []rune(tok)[i]
-				}
+	// String literal
+	if t.currentRune == '"' {
+		return t.readString()
+	}
 
-				return false
-			}) {
-				t.Token += string(t.currentRune)
-				t.NextRune()
-			}
+	// Known tokens, preferring the longest match so that == is not read as =
+	if tok := t.matchKnownToken(); tok != "" {
+		for range []rune(tok) {
+			t.NextRune()
 		}
 
-		return
+		t.Token = tok
+		return nil
 	}
 
-	// Identifier - starts with letter or underscore
+	// Identifier - starts with letter or underscore, and may contain digits after that
 	if isPartOfIdentifier(t.currentRune) {
 		sb := strings.Builder{}
 
-		for isPartOfIdentifier(t.currentRune) {
+		for isPartOfIdentifier(t.currentRune) || unicode.IsDigit(t.currentRune) {
 			sb.WriteRune(t.currentRune)
 			t.NextRune()
 		}
 
-		// Setup token
+		// Setup token, word operators are only recognised as whole words so that names such as index are still identifiers
 		t.Identifier = sb.String()
 		t.Token = identifier
+		if slices.Contains(t.reg.wordTokens, t.Identifier) {
+			t.Token = t.Identifier
+		}
+
 		return nil
 	}
 
@@ -111,8 +105,9 @@ func (t *tokenizer) NextToken() (err error) {
 			t.NextRune()
 		}
 
-		// Parse it
-		t.Number, err = strconv.ParseFloat(sb.String(), 64)
+		// Parse it, keeping the text for exact decimals
+		t.NumberText = sb.String()
+		t.Number, err = strconv.ParseFloat(t.NumberText, 64)
 		if err != nil {
 			return fmt.Errorf("error parsing float: %w", err)
 		}
@@ -125,6 +120,62 @@ func (t *tokenizer) NextToken() (err error) {
 	return fmt.Errorf("unexpected character: %c", t.currentRune)
 }
 
+// matchKnownToken returns the longest known token starting at the current rune
+func (t *tokenizer) matchKnownToken() string {
+	remaining := t.runes[t.position-1:]
+
+	match := ""
+	for _, tok := range t.reg.knownTokens {
+		runes := []rune(tok)
+		if len(runes) > len(match) && len(runes) <= len(remaining) && slices.Equal(runes, remaining[:len(runes)]) {
+			match = tok
+		}
+	}
+
+	return match
+}
+
+// readString reads a quoted string, the escapes \" \\ \n and \t are supported. Any other escape is left as is so
+// that patterns such as regular expressions can be written without doubling up backslashes
+func (t *tokenizer) readString() error {
+	sb := strings.Builder{}
+
+	// Skip the opening quote
+	t.NextRune()
+	for t.currentRune != '"' {
+		switch t.currentRune {
+		case '\000':
+			return errors.New("unterminated string")
+		case '\\':
+			t.NextRune()
+			switch t.currentRune {
+			case '"', '\\':
+				sb.WriteRune(t.currentRune)
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case '\000':
+				return errors.New("unterminated string")
+			default:
+				sb.WriteRune('\\')
+				sb.WriteRune(t.currentRune)
+			}
+		default:
+			sb.WriteRune(t.currentRune)
+		}
+
+		t.NextRune()
+	}
+
+	// Skip the closing quote
+	t.NextRune()
+
+	t.String = sb.String()
+	t.Token = str
+	return nil
+}
+
 // Read the next character from the input stream
 // and store it in currentRune, or load '\000' if EOF
 func (t *tokenizer) NextRune() {
@@ -139,3 +190,14 @@ func (t *tokenizer) NextRune() {
 func isPartOfIdentifier(r rune) bool {
 	return unicode.IsLetter(r) || r == '_' || r == '.'
 }
+
+// isWord checks whether the token would be read as an identifier
+func isWord(token string) bool {
+	for i, r := range token {
+		if !isPartOfIdentifier(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return token != ""
+}
@@ -1,6 +1,7 @@
 package parsley
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/scottkgregory/parsley/internal/assert"
@@ -41,11 +42,11 @@ func TestTokenizer(t *testing.T) {
 		},
 		{
 			input:  `foo == "hello"`,
-			tokens: []string{identifier, "==", `"`, identifier, `"`, eof},
+			tokens: []string{identifier, "==", str, eof},
 		},
 		{
 			input:  "foo.bar.baz == \"hello\"",
-			tokens: []string{identifier, "==", `"`, identifier, `"`, eof},
+			tokens: []string{identifier, "==", str, eof},
 		},
 		{
 			input:  "foo.bar.baz == 6",
@@ -73,19 +74,19 @@ func TestTokenizer(t *testing.T) {
 		},
 		{
 			input:  `(object_attributes.state == "opened") && (object_attributes.labels.title == "automated")`,
-			tokens: []string{"(", identifier, "==", `"`, identifier, `"`, ")", "&&", "(", identifier, "==", `"`, identifier, `"`, ")", eof},
+			tokens: []string{"(", identifier, "==", str, ")", "&&", "(", identifier, "==", str, ")", eof},
 		},
 		{
 			input:  `(object_attributes.state == "opened") && contains_any(object_attributes.labels, "title", "automated")`,
-			tokens: []string{"(", identifier, "==", `"`, identifier, `"`, ")", "&&", identifier, "(", identifier, ",", `"`, identifier, `"`, ",", `"`, identifier, `"`, ")", eof},
+			tokens: []string{"(", identifier, "==", str, ")", "&&", identifier, "(", identifier, ",", str, ",", str, ")", eof},
 		},
 		{
 			input:  `(object_attributes.state == "opened") && contains_any(object_attributes.labels, "title", 70)`,
-			tokens: []string{"(", identifier, "==", `"`, identifier, `"`, ")", "&&", identifier, "(", identifier, ",", `"`, identifier, `"`, ",", number, ")", eof},
+			tokens: []string{"(", identifier, "==", str, ")", "&&", identifier, "(", identifier, ",", str, ",", number, ")", eof},
 		},
 		{
 			input:  `(object_attributes.state == "opened") || contains_any(object_attributes.labels, "title", 70)`,
-			tokens: []string{"(", identifier, "==", `"`, identifier, `"`, ")", "||", identifier, "(", identifier, ",", `"`, identifier, `"`, ",", number, ")", eof},
+			tokens: []string{"(", identifier, "==", str, ")", "||", identifier, "(", identifier, ",", str, ",", number, ")", eof},
 		},
 		{
 			input:  `object_attributes.state`,
@@ -95,11 +96,35 @@ func TestTokenizer(t *testing.T) {
 			input:  `-2`,
 			tokens: []string{"-", number, eof},
 		},
+		{
+			input:  `("a", "b c")`,
+			tokens: []string{"(", str, ",", str, ")", eof},
+		},
+		{
+			input:  `log10(x2) + 2`,
+			tokens: []string{identifier, "(", identifier, ")", "+", number, eof},
+		},
+		{
+			input:  `""`,
+			tokens: []string{str, eof},
+		},
+		{
+			input:  `(a<2)&&(b=~"c")||(d!~"e")`,
+			tokens: []string{"(", identifier, "<", number, ")", "&&", "(", identifier, "=~", str, ")", "||", "(", identifier, "!~", str, ")", eof},
+		},
+		{
+			input:  `index in inside`,
+			tokens: []string{identifier, "in", identifier, eof},
+		},
+		{
+			input:  `any(xs, x=>x==1)`,
+			tokens: []string{identifier, "(", identifier, ",", identifier, "=>", identifier, "==", number, ")", eof},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.input, func(tt *testing.T) {
-			tok, err := newTokenizer(tc.input, newRegistry())
+			tok, err := newTokenizer(tc.input, NewRegistry().load())
 			assert.Equal(tt, nil, err)
 
 			toks := []string{}
@@ -114,3 +139,32 @@ func TestTokenizer(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenizerStrings(t *testing.T) {
+	testCases := []struct {
+		input  string
+		result string
+		err    error
+	}{
+		{`"hello"`, "hello", nil},
+		{`""`, "", nil},
+		{`"hello world, (1 + 2)"`, "hello world, (1 + 2)", nil},
+		{`"say \"hi\""`, `say "hi"`, nil},
+		{`"a\\b"`, `a\b`, nil},
+		{`"a\nb\tc"`, "a\nb\tc", nil},
+		{`"release/v\d+\.\d+"`, `release/v\d+\.\d+`, nil},
+		{`"£€"`, "£€", nil},
+		{`"unterminated`, "", errors.New("unterminated string")},
+		{`"unterminated\`, "", errors.New("unterminated string")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			tok, err := newTokenizer(tc.input, NewRegistry().load())
+			assert.ErrorEqual(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, str, tok.Token)
+				assert.Equal(t, tc.result, tok.String)
+			}
+		})
+	}
+}
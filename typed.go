@@ -0,0 +1,303 @@
+package parsley
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/scottkgregory/parsley/internal/helpers"
+	"github.com/scottkgregory/parsley/internal/types"
+)
+
+// FunctionRegistrar is implemented by anything functions can be registered with
+type FunctionRegistrar interface {
+	RegisterFunction(name string, fun Function, sig ...Signature)
+}
+
+var _ FunctionRegistrar = &Parser{}
+
+// RegisterFunc0 registers a function which takes no arguments
+func RegisterFunc0[R any](r FunctionRegistrar, name string, fun func() (R, error)) {
+	registerBoundFunc0(r, name, func(environment) func() (R, error) { return fun })
+}
+
+// RegisterFunc1 registers a function which takes a single argument, converting it to the parameter type before the function is called
+func RegisterFunc1[A, R any](r FunctionRegistrar, name string, fun func(A) (R, error)) {
+	registerBoundFunc1(r, name, func(environment) func(A) (R, error) { return fun })
+}
+
+// RegisterFunc2 registers a function which takes two arguments, converting them to the parameter types before the function is called
+func RegisterFunc2[A, B, R any](r FunctionRegistrar, name string, fun func(A, B) (R, error)) {
+	registerBoundFunc2(r, name, func(environment) func(A, B) (R, error) { return fun })
+}
+
+// RegisterFunc3 registers a function which takes three arguments, converting them to the parameter types before the function is called
+func RegisterFunc3[A, B, C, R any](r FunctionRegistrar, name string, fun func(A, B, C) (R, error)) {
+	registerBoundFunc3(r, name, func(environment) func(A, B, C) (R, error) { return fun })
+}
+
+// RegisterFuncVariadic registers a function which takes any number of arguments of the same type, converting them
+// before the function is called
+func RegisterFuncVariadic[A, R any](r FunctionRegistrar, name string, fun func(...A) (R, error)) {
+	registerBound(r, name, func(env environment) Function {
+		coercion := env.semantics.Coercion
+		return func(args ...any) (any, error) {
+			converted := make([]A, len(args))
+			for i, arg := range args {
+				var err error
+				converted[i], err = convertArg[A](name, i, arg, coercion)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return fun(converted...)
+		}
+	}, Signature{Params: []Param{paramFor[A]("a")}, Variadic: true, Returns: typeFor[R]()})
+}
+
+// registerBoundFunc0 registers a function which takes no arguments, and is bound to the parser evaluating it
+func registerBoundFunc0[R any](r FunctionRegistrar, name string, bind func(env environment) func() (R, error)) {
+	registerBound(r, name, func(env environment) Function {
+		fun := bind(env)
+		return func(args ...any) (any, error) {
+			err := checkArgCount(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			return fun()
+		}
+	}, Signature{Returns: typeFor[R]()})
+}
+
+// registerBoundFunc1 registers a function which takes a single argument, and is bound to the parser evaluating it.
+// The argument is converted using the parser's coercion policy
+func registerBoundFunc1[A, R any](r FunctionRegistrar, name string, bind func(env environment) func(A) (R, error)) {
+	registerBound(r, name, func(env environment) Function {
+		fun, coercion := bind(env), env.semantics.Coercion
+		return func(args ...any) (any, error) {
+			err := checkArgCount(name, args, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			a, err := convertArg[A](name, 0, args[0], coercion)
+			if err != nil {
+				return nil, err
+			}
+
+			return fun(a)
+		}
+	}, Signature{Params: []Param{paramFor[A]("a")}, Returns: typeFor[R]()})
+}
+
+// registerBoundFunc2 registers a function which takes two arguments, and is bound to the parser evaluating it. The
+// arguments are converted using the parser's coercion policy
+func registerBoundFunc2[A, B, R any](r FunctionRegistrar, name string, bind func(env environment) func(A, B) (R, error)) {
+	registerBound(r, name, func(env environment) Function {
+		fun, coercion := bind(env), env.semantics.Coercion
+		return func(args ...any) (any, error) {
+			err := checkArgCount(name, args, 2)
+			if err != nil {
+				return nil, err
+			}
+
+			a, err := convertArg[A](name, 0, args[0], coercion)
+			if err != nil {
+				return nil, err
+			}
+
+			b, err := convertArg[B](name, 1, args[1], coercion)
+			if err != nil {
+				return nil, err
+			}
+
+			return fun(a, b)
+		}
+	}, Signature{Params: []Param{paramFor[A]("a"), paramFor[B]("b")}, Returns: typeFor[R]()})
+}
+
+// registerBoundFunc3 registers a function which takes three arguments, and is bound to the parser evaluating it.
+// The arguments are converted using the parser's coercion policy
+func registerBoundFunc3[A, B, C, R any](r FunctionRegistrar, name string, bind func(env environment) func(A, B, C) (R, error)) {
+	registerBound(r, name, func(env environment) Function {
+		fun, coercion := bind(env), env.semantics.Coercion
+		return func(args ...any) (any, error) {
+			err := checkArgCount(name, args, 3)
+			if err != nil {
+				return nil, err
+			}
+
+			a, err := convertArg[A](name, 0, args[0], coercion)
+			if err != nil {
+				return nil, err
+			}
+
+			b, err := convertArg[B](name, 1, args[1], coercion)
+			if err != nil {
+				return nil, err
+			}
+
+			c, err := convertArg[C](name, 2, args[2], coercion)
+			if err != nil {
+				return nil, err
+			}
+
+			return fun(a, b, c)
+		}
+	}, Signature{Params: []Param{paramFor[A]("a"), paramFor[B]("b"), paramFor[C]("c")}, Returns: typeFor[R]()})
+}
+
+func checkArgCount(name string, args []any, count int) error {
+	if len(args) != count {
+		return fmt.Errorf("%w: function %s expects %d arguments, got %d", ErrInvalidArity, name, count, len(args))
+	}
+
+	return nil
+}
+
+// convertArg converts an argument to the type expected by a typed function using the same rules as the rest of
+// the parser, falling back to a plain type assertion for anything else
+func convertArg[T any](name string, i int, v any, coercion helpers.CoercionPolicy) (T, error) {
+	ret, err := convertValue(reflect.TypeFor[T](), v, coercion)
+	if err != nil {
+		return *new(T), fmt.Errorf("%w %d to function %s: %w", ErrInvalidArgument, i, name, err)
+	}
+
+	if ret == nil {
+		return *new(T), nil
+	}
+
+	return ret.(T), nil
+}
+
+func convertValue(t reflect.Type, v any, coercion helpers.CoercionPolicy) (any, error) {
+	// Values that already have the right type, such as a time.Duration from the data, need no conversion
+	if v != nil && reflect.TypeOf(v).AssignableTo(t) {
+		return v, nil
+	}
+
+	var ret any
+	var err error
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		ret, err = coercion.ToFloat64(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		ret, err = toInteger(t, v, coercion)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		ret, err = toUnsigned(t, v, coercion)
+	case reflect.String:
+		ret, err = coercion.ToString(v)
+	case reflect.Bool:
+		ret, err = coercion.ToBool(v)
+	case reflect.Slice:
+		return convertSlice(t, v, coercion)
+	default:
+		if v == nil {
+			return nil, nil
+		}
+
+		if !reflect.TypeOf(v).AssignableTo(t) {
+			return nil, fmt.Errorf("expected %s, got %T", t, v)
+		}
+
+		return v, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Named types such as time.Duration need converting from the underlying type
+	return reflect.ValueOf(ret).Convert(t).Interface(), nil
+}
+
+func convertSlice(t reflect.Type, v any, coercion helpers.CoercionPolicy) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Type().AssignableTo(t) {
+		return v, nil
+	}
+
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected %s, got %T", t, v)
+	}
+
+	ret := reflect.MakeSlice(t, rv.Len(), rv.Len())
+	for i := range rv.Len() {
+		item, err := convertValue(t.Elem(), rv.Index(i).Interface(), coercion)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		if item != nil {
+			ret.Index(i).Set(reflect.ValueOf(item))
+		}
+	}
+
+	return ret.Interface(), nil
+}
+
+// toInt converts the value to an int following the coercion policy
+func toInt(v any, coercion helpers.CoercionPolicy) (int, error) {
+	i, err := toInteger(reflect.TypeFor[int](), v, coercion)
+	return int(i), err
+}
+
+// toInteger converts the value to a whole number which fits in the integer type, without going through a float so
+// that large integers keep their precision
+func toInteger(t reflect.Type, v any, coercion helpers.CoercionPolicy) (int64, error) {
+	i, err := coercion.ToInt64(v)
+	if err != nil {
+		return 0, err //nolint:wrapcheck // Error does not need to be wrapped here
+	}
+
+	if reflect.Zero(t).OverflowInt(i) {
+		return 0, fmt.Errorf("%w, %v is out of range for %s", helpers.ErrInvalidInt, v, t)
+	}
+
+	return i, nil
+}
+
+// toUnsigned converts the value to a whole number which fits in the unsigned integer type
+func toUnsigned(t reflect.Type, v any, coercion helpers.CoercionPolicy) (uint64, error) {
+	var u uint64
+	if rv := reflect.ValueOf(v); rv.CanUint() {
+		// Unsigned values may be too large for an int64
+		u = rv.Uint()
+	} else {
+		i, err := coercion.ToInt64(v)
+		if err != nil {
+			return 0, err //nolint:wrapcheck // Error does not need to be wrapped here
+		}
+
+		if i < 0 {
+			return 0, fmt.Errorf("%w, %v is out of range for %s", helpers.ErrInvalidInt, v, t)
+		}
+
+		u = uint64(i)
+	}
+
+	if reflect.Zero(t).OverflowUint(u) {
+		return 0, fmt.Errorf("%w, %v is out of range for %s", helpers.ErrInvalidInt, v, t)
+	}
+
+	return u, nil
+}
+
+func typeFor[T any]() Type {
+	t, err := types.FromGo(reflect.TypeFor[T]())
+	if err != nil {
+		return types.Any
+	}
+
+	return t
+}
+
+func paramFor[T any](name string) Param {
+	return Param{Name: name, Type: typeFor[T]()}
+}
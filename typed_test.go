@@ -0,0 +1,136 @@
+package parsley
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottkgregory/parsley/internal/assert"
+)
+
+func TestRegisterTypedFunctions(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	RegisterFunc0(parser, "answer", func() (int, error) { return 42, nil })
+	RegisterFunc1(parser, "double", func(x int) (int, error) { return x * 2, nil })
+	RegisterFunc1(parser, "seconds", func(d time.Duration) (float64, error) { return d.Seconds(), nil })
+	RegisterFunc1(parser, "count", func(x []string) (int, error) { return len(x), nil })
+	RegisterFunc2(parser, "repeat", func(s string, n int) (string, error) { return strings.Repeat(s, n), nil })
+	RegisterFunc3(parser, "between", func(x, lo, hi float64) (bool, error) { return x >= lo && x <= hi, nil })
+	RegisterFuncVariadic(parser, "sum", func(xs ...float64) (float64, error) {
+		total := 0.0
+		for _, x := range xs {
+			total += x
+		}
+		return total, nil
+	})
+	RegisterFunc1(parser, "fail", func(_ any) (any, error) { return nil, errors.New("uh oh") })
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`answer()`, 42, nil},
+		{`double(21)`, 42, nil},
+		{`double(num)`, 84, nil},
+		{`double(2.5)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function double: " +
+			"error parsing value as int, 2.5 is not a whole number")},
+		{`double(word)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function double: " +
+			"error parsing value as float, could not parse string 'hello'")},
+		{`seconds(1000000000)`, float64(1), nil},
+		{`count(list)`, 3, nil},
+		{`count(nothing)`, 0, nil},
+		{`count(word)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function count: expected list, got string")},
+		{`repeat(word, 2)`, "hellohello", nil},
+		{`between(2, 1, 3)`, true, nil},
+		{`between(5, 1, 3)`, false, nil},
+		{`sum()`, float64(0), nil},
+		{`sum(1, 2, num)`, float64(45), nil},
+		{`fail(1)`, nil, errors.New("error evaluating expression: node evaluation failed: uh oh")},
+		{`double()`, nil, errors.New("invalid number of arguments: function double expects 1 arguments, got 0")},
+		{`between(1, 2)`, nil, errors.New("invalid number of arguments: function between expects 3 arguments, got 2")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, map[string]any{
+				"num":  42,
+				"word": "hello",
+				"list": []any{"a", "b", "c"},
+			})
+			assert.Equal(t, tc.result, actual)
+			assert.ErrorEqual(t, tc.err, err)
+		})
+	}
+}
+
+func TestTypedFunctionIntegers(t *testing.T) {
+	parser, err := New(WithIntegers())
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	RegisterFunc1(parser, "next", func(x int64) (int64, error) { return x + 1, nil })
+	RegisterFunc1(parser, "byte", func(x uint8) (uint8, error) { return x, nil })
+	RegisterFunc1(parser, "small", func(x int32) (int32, error) { return x, nil })
+	RegisterFunc1(parser, "unsigned", func(x uint64) (uint64, error) { return x, nil })
+
+	testCases := []struct {
+		input  string
+		result any
+		err    error
+	}{
+		{`next(big)`, int64(9007199254740993), nil},
+		{`next(9007199254740992)`, int64(9007199254740993), nil},
+		{`byte(255)`, uint8(255), nil},
+		{`byte(256)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function byte: " +
+			"error parsing value as int, 256 is out of range for uint8")},
+		{`byte(-1)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function byte: " +
+			"error parsing value as int, -1 is out of range for uint8")},
+		{`small(-2147483648)`, int32(-2147483648), nil},
+		{`small(2147483648)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function small: " +
+			"error parsing value as int, 2147483648 is out of range for int32")},
+		{`unsigned(huge)`, uint64(math.MaxUint64), nil},
+		{`next(float)`, nil, errors.New("error evaluating expression: node evaluation failed: invalid argument 0 to function next: " +
+			"error parsing value as int, 9.223372036854776e+18 is out of range")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parser.ParseAsAny(tc.input, map[string]any{
+				"big":   int64(9007199254740992),
+				"huge":  uint64(math.MaxUint64),
+				"float": math.Pow(2, 63),
+			})
+			assert.ErrorEqual(t, tc.err, err)
+			assert.Equal(t, tc.result, actual)
+		})
+	}
+}
+
+func TestTypedFunctionDirectCall(t *testing.T) {
+	parser, err := NewParser(false)
+	assert.Nil(t, err)
+	defer parser.Close()
+
+	RegisterFunc2(parser, "pair", func(a []int, b map[string]any) (string, error) { return "", nil })
+	fun := parser.Registry.load().functions["pair"]
+
+	_, err = fun(1)
+	assert.ErrorEqual(t, errors.New("invalid number of arguments: function pair expects 2 arguments, got 1"), err)
+	assert.ErrorIs(t, ErrInvalidArity, err)
+
+	_, err = fun([]any{1, "b"}, nil)
+	assert.ErrorEqual(t, errors.New("invalid argument 0 to function pair: item 1: error parsing value as int, could not parse string 'b'"), err)
+	assert.ErrorIs(t, ErrInvalidArgument, err)
+
+	_, err = fun([]int{1}, []string{})
+	assert.ErrorEqual(t, errors.New("invalid argument 1 to function pair: expected map[string]interface {}, got []string"), err)
+
+	sig := parser.Registry.load().signatures["pair"]
+	assert.Equal(t, "list<number>", sig.Params[0].Type.String())
+	assert.Equal(t, "map<any>", sig.Params[1].Type.String())
+	assert.Equal(t, "string", sig.Returns.String())
+}